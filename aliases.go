@@ -0,0 +1,282 @@
+// Copyright © 2020 Hedzr Yeh.
+
+package cmdr
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// aliasesFile is the file in the sub-directory `conf.d` where the
+// `alias set` and `alias remove` commands save the aliases.
+const aliasesFile = "aliases.yml"
+
+// buildAliases registers each entry of the config section `app.aliases`
+// as a command of root, in the Aliases group, such as:
+//
+//     app:
+//       aliases:
+//         co: checkout --force
+//         st: [status, --short]
+//
+// The value is a command-line in the shell style, or a list of args. An
+// alias can't override a command of root. The aliases registered ever
+// are replaced, so that it can be rebuilt after the config changed.
+func (w *ExecWorker) buildAliases(root *RootCommand) {
+	for name, cc := range root.plainCmds {
+		if cc.alias != nil {
+			delete(root.plainCmds, name)
+		}
+	}
+	var cmds []*Command
+	for _, cc := range root.SubCommands {
+		if cc.alias == nil {
+			cmds = append(cmds, cc)
+		}
+	}
+	root.SubCommands = cmds
+	delete(root.allCmds, AliasesGroup)
+
+	for name, val := range w.aliases() {
+		if _, ok := root.plainCmds[name]; ok {
+			flog("    [aliases] %q is ignored, it's a command already", name)
+			continue
+		}
+		args, err := aliasArgs(val)
+		if err != nil || len(args) == 0 {
+			flog("    [aliases] %q is ignored: %v", name, err)
+			continue
+		}
+		cx := &Command{
+			BaseOpt: BaseOpt{
+				Full:        name,
+				Description: fmt.Sprintf("alias for '%v'", strings.Join(args, " ")),
+				Group:       AliasesGroup,
+				owner:       &root.Command,
+			},
+			alias: args,
+		}
+		w.ensureCmdMembers(cx)
+		if _, ok := root.allCmds[AliasesGroup]; !ok {
+			root.allCmds[AliasesGroup] = make(map[string]*Command)
+		}
+		root.SubCommands = append(root.SubCommands, cx)
+		root.allCmds[AliasesGroup][name] = cx
+		root.plainCmds[name] = cx
+	}
+}
+
+// aliases returns the entries of the config section `app.aliases`.
+func (w *ExecWorker) aliases() (m map[string]interface{}) {
+	s, prefix := w.rxxtOptions, w.wrapWithRxxtPrefix("aliases")+"."
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+	m = make(map[string]interface{})
+	for key, val := range s.entries {
+		if name := strings.TrimPrefix(key, prefix); name != key && !strings.Contains(name, ".") {
+			m[name] = val
+		}
+	}
+	return
+}
+
+// aliasArgs returns the args of an alias, which is a command-line or a
+// list of args.
+func aliasArgs(val interface{}) (args []string, err error) {
+	switch x := val.(type) {
+	case string:
+		args, _, err = splitResponseFile(x)
+	case []string:
+		args = x
+	case []interface{}:
+		for _, v := range x {
+			args = append(args, fmt.Sprint(v))
+		}
+	default:
+		err = fmt.Errorf("%v is not a command-line", val)
+	}
+	return
+}
+
+// expandAlias replaces the alias in args[pkg.i] with its args, if cmd
+// is root. An alias can be expanded into another one.
+func (w *ExecWorker) expandAlias(pkg *ptpkg, cmd *Command, args []string) (ret []string, err error) {
+	ret = args
+	if cmd.owner != nil || pkg.optionsEnded || pkg.lastCommandHeld {
+		return
+	}
+	seen := make(map[string]bool)
+	for {
+		cc, ok := cmd.plainCmds[ret[pkg.i]]
+		if !ok || cc.alias == nil {
+			return
+		}
+		if seen[cc.Full] {
+			err = newError(false, errAliasLoop, args[pkg.i], cmd.GetName())
+			return
+		}
+		seen[cc.Full] = true
+		flog("    -> alias %q expanded: %q", cc.Full, cc.alias)
+		ret = append(append(append([]string{}, ret[:pkg.i]...), cc.alias...), ret[pkg.i+1:]...)
+	}
+}
+
+// attachAliasCommands adds the `alias` command to manage the aliases,
+// see also WithAliasCommands.
+func (w *ExecWorker) attachAliasCommands(root *RootCommand) {
+	if !w.enableAliasCommands {
+		return
+	}
+	if _, ok := root.allCmds[SysMgmtGroup]["alias"]; ok {
+		return
+	}
+
+	cx := &Command{
+		BaseOpt: BaseOpt{
+			Full:        "alias",
+			Description: "manage the command aliases in the config file",
+			Group:       SysMgmtGroup,
+			owner:       &root.Command,
+		},
+	}
+	cx.SubCommands = []*Command{
+		{
+			BaseOpt: BaseOpt{
+				Full:        "list",
+				Short:       "ls",
+				Description: "list the aliases",
+				owner:       cx,
+				Action: func(cmd *Command, args []string) (err error) {
+					w := cmd.worker()
+					m := w.aliases()
+					var names []string
+					for name := range m {
+						names = append(names, name)
+					}
+					sort.Strings(names)
+					for _, name := range names {
+						a, _ := aliasArgs(m[name])
+						w.fp("%v = %v", name, strings.Join(a, " "))
+					}
+					return
+				},
+			},
+		},
+		{
+			BaseOpt: BaseOpt{
+				Full:        "set",
+				Description: "add or change an alias, such as: alias set co 'checkout --force'",
+				owner:       cx,
+				Action: func(cmd *Command, args []string) (err error) {
+					if len(args) < 2 {
+						return newError(false, errWrongArgs, "expecting <name> <command-line>", cmd.GetName())
+					}
+					line := strings.Join(args[1:], " ")
+					if len(args) > 2 {
+						line = quoteArgs(args[1:])
+					}
+					return cmd.worker().saveAlias(args[0], line)
+				},
+			},
+			TailPlaceHolder: "<name> <command-line>",
+		},
+		{
+			BaseOpt: BaseOpt{
+				Full:        "remove",
+				Short:       "rm",
+				Description: "remove an alias",
+				owner:       cx,
+				Action: func(cmd *Command, args []string) (err error) {
+					if len(args) != 1 {
+						return newError(false, errWrongArgs, "expecting <name>", cmd.GetName())
+					}
+					return cmd.worker().saveAlias(args[0], "")
+				},
+			},
+			TailPlaceHolder: "<name>",
+		},
+	}
+	root.SubCommands = uniAddCmd(root.SubCommands, cx)
+	root.allCmds[SysMgmtGroup]["alias"] = cx
+	root.plainCmds["alias"] = cx
+}
+
+// saveAlias sets the alias name to line, or removes it if line is
+// empty, and saves it into `conf.d/aliases.yml` beside the main config
+// file. An alias defined in the other config files can't be removed.
+func (w *ExecWorker) saveAlias(name, line string) (err error) {
+	root, s := w.rootCommand, w.rxxtOptions
+	if cc, ok := root.plainCmds[name]; ok && cc.alias == nil || strings.ContainsAny(name, " \t\r\n") || strings.HasPrefix(name, "-") {
+		return newError(false, errAliasSave, name, fmt.Errorf("it's a command, or not a valid name"))
+	}
+	if line != "" {
+		if a, _, e := splitResponseFile(line); e != nil || len(a) == 0 {
+			return newError(false, errAliasSave, name, fmt.Errorf("%q is not a command-line: %v", line, e))
+		}
+	}
+	if s.usedConfigFile == "" {
+		return newError(false, errAliasSave, name, fmt.Errorf("no config file is loaded"))
+	}
+
+	file := filepath.Join(filepath.Dir(s.usedConfigFile), "conf.d", aliasesFile)
+	m := make(map[string]interface{})
+	if b, e := ioutil.ReadFile(file); e == nil {
+		if err = yaml.Unmarshal(b, &m); err != nil {
+			return newError(false, errAliasSave, name, err)
+		}
+	}
+	node := m
+	for _, k := range append(append([]string{}, w.rxxtPrefixes...), "aliases") {
+		child, ok := node[k].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			node[k] = child
+		}
+		node = child
+	}
+
+	key := w.wrapWithRxxtPrefix("aliases." + name)
+	if line == "" {
+		if _, ok := node[name]; !ok {
+			if s.Has(key) {
+				return newError(false, errAliasSave, name, fmt.Errorf("it's not defined in %v", file))
+			}
+			return newError(false, errAliasSave, name, fmt.Errorf("no such alias"))
+		}
+		delete(node, name)
+		s.Delete(key)
+	} else {
+		node[name] = line
+		s.SetNx(key, line)
+	}
+
+	var b []byte
+	if b, err = yaml.Marshal(m); err == nil {
+		if err = os.MkdirAll(filepath.Dir(file), 0755); err == nil {
+			err = ioutil.WriteFile(file, b, 0644)
+		}
+	}
+	if err != nil {
+		return newError(false, errAliasSave, name, err)
+	}
+	w.buildAliases(root)
+	return
+}
+
+// quoteArgs joins args into a command-line, the args are single-quoted
+// if necessary.
+func quoteArgs(args []string) string {
+	a := make([]string, len(args))
+	for i, s := range args {
+		if s == "" || strings.ContainsAny(s, " \t\r\n'\"\\#$`") {
+			s = "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+		}
+		a[i] = s
+	}
+	return strings.Join(a, " ")
+}
@@ -0,0 +1,89 @@
+// Copyright © 2020 Hedzr Yeh.
+
+package cmdr_test
+
+import (
+	"fmt"
+	"github.com/hedzr/cmdr"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestAliases(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cmdr-aliases")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cfg := path.Join(dir, "als.yml")
+	if err = ioutil.WriteFile(cfg, []byte(`app:
+  aliases:
+    co: checkout --force
+    cn: [checkout, -n, "a b"]
+    loop1: loop2 -f
+    loop2: loop1
+    checkout: help
+    empty: ""
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &cmdrTester{T: t}
+	c.newRoot = func() *cmdr.RootCommand {
+		root := cmdr.Root("als", "1.0.0")
+		cmdr.NewString().Titles("profile", "pr").AttachTo(root)
+		cmd := root.NewSubCommand("checkout").Action(c.action)
+		cmdr.NewBool().Titles("force", "f").AttachTo(cmd)
+		cmdr.NewString().Titles("name", "n").AttachTo(cmd)
+		return root.RootCommand()
+	}
+	c.result = func(w *cmdr.ExecWorker) string {
+		if c.cmd == nil {
+			return none
+		}
+		opts, key := optionsOf(w, "")
+		return fmt.Sprintf("pr=%q f=%v n=%q %q", opts.GetString(key("profile")),
+			opts.GetBoolEx(key("checkout.force")), opts.GetString(key("checkout.name")), c.args)
+	}
+	c.opts = []cmdr.ExecOption{
+		cmdr.WithNoLoadConfigFiles(false),
+		cmdr.WithPredefinedLocations(cfg),
+		cmdr.WithNoWatchConfigFiles(true),
+		cmdr.WithAliasCommands(true),
+	}
+	// the args of 'alias set' are split by '|'
+	c.split = func(args string) []string {
+		argv := strings.Split(args, " ")
+		if strings.Contains(args, "|") {
+			argv = append(argv[:2], strings.Split(strings.Join(argv[2:], " "), "|")...)
+		}
+		return argv
+	}
+
+	c.expect(conformance{
+		{"checkout x", `pr="" f=false n="" ["x"]`},
+		{"co x", `pr="" f=true n="" ["x"]`},
+		{"--profile p co -n y x", `pr="p" f=true n="y" ["x"]`},
+		{"cn", `pr="" f=false n="a b" []`},
+		{"checkout co", `pr="" f=false n="" ["co"]`},
+		{"empty", none},
+		{"loop1", "the alias 'loop1' expands into itself, under command 'als'"},
+		{"alias set checkout x", "cannot save the alias 'checkout': it's a command"},
+		{"alias remove co", "cannot save the alias 'co': it's not defined in " + path.Join(dir, "conf.d", "aliases.yml")},
+		{"alias remove none", "cannot save the alias 'none': no such alias"},
+		{"alias set", "invalid arguments: expecting <name> <command-line>, under command 'set'"},
+		{"alias set ci|checkout -n ci", none},
+		{"ci x", `pr="" f=false n="ci" ["x"]`},
+		{"alias set ci|checkout|it's", none},
+		{"ci", `pr="" f=false n="" ["it's"]`},
+		{"alias remove ci", none},
+		{"ci", none},
+		{"alias set st|checkout -f", none},
+	})
+	c.expectOutput("alias list", "co = checkout --force\n", "cn = checkout -n a b\n", "st = checkout -f\n")
+	c.expectOutput("--help", "Aliases", "co", "alias for 'checkout --force'", "st", "alias for 'checkout -f'", "alias")
+	c.expectNoOutput("--help", "alias for 'help'") // the alias checkout is ignored
+}
@@ -0,0 +1,226 @@
+// Copyright © 2020 Hedzr Yeh.
+
+package cmdr
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Arg returns the text of the positional argument name, or the text of
+// its DefaultValue if absent. For a variadic one, it returns the first
+// text, see also ArgStrings.
+func (c *Command) Arg(name string) string {
+	if a := c.argValues[name]; len(a) > 0 {
+		return a[0]
+	}
+	if arg := c.findArg(name); arg != nil && arg.DefaultValue != nil {
+		return fmt.Sprint(arg.DefaultValue)
+	}
+	return ""
+}
+
+// ArgStrings returns the texts of the positional argument name, it's
+// useful for a variadic one.
+func (c *Command) ArgStrings(name string) []string {
+	return c.argValues[name]
+}
+
+// ArgValue returns the typed value of the positional argument name, or
+// its DefaultValue if absent. For a variadic one, it returns a slice,
+// such as []int.
+func (c *Command) ArgValue(name string) interface{} {
+	if v, ok := c.argTypedValues[name]; ok {
+		return v
+	}
+	if arg := c.findArg(name); arg != nil {
+		if arg.Variadic {
+			return arg.typedSlice(nil)
+		}
+		return arg.DefaultValue
+	}
+	return nil
+}
+
+func (c *Command) findArg(name string) *PositionalArg {
+	for _, arg := range c.Args {
+		if arg.Name == name {
+			return arg
+		}
+	}
+	return nil
+}
+
+// argAt returns the declaration of the i-th positional argument.
+func (c *Command) argAt(i int) *PositionalArg {
+	if i < len(c.Args) {
+		return c.Args[i]
+	}
+	if n := len(c.Args); n > 0 && c.Args[n-1].Variadic {
+		return c.Args[n-1]
+	}
+	return nil
+}
+
+// tailPlaceHolder returns TailPlaceHolder, or the usage of Args, such
+// as "<host> [port] [files...]".
+func (c *Command) tailPlaceHolder() string {
+	if len(c.TailPlaceHolder) > 0 || len(c.Args) == 0 {
+		return c.TailPlaceHolder
+	}
+	var a []string
+	for _, arg := range c.Args {
+		s := arg.Name
+		if arg.Variadic {
+			s += "..."
+		}
+		if arg.Required {
+			s = "<" + s + ">"
+		} else {
+			s = "[" + s + "]"
+		}
+		a = append(a, s)
+	}
+	return strings.Join(a, " ")
+}
+
+// hasArgCandidates reports whether the positional arguments of cmd have
+// the completion candidates.
+func (c *Command) hasArgCandidates() bool {
+	if c.Completer != nil {
+		return true
+	}
+	for _, arg := range c.Args {
+		if arg.Completer != nil || len(arg.ValidArgs) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// checkArgsDecl validates the positional arguments declared by cmd
+// and its sub-commands, only the last one can be Variadic.
+func checkArgsDecl(cmd *Command) (err error) {
+	return walkFromCommand(cmd, 0, func(cc *Command, index int) (err error) {
+		for i, arg := range cc.Args {
+			if arg.Variadic && i < len(cc.Args)-1 {
+				return newError(false, errVariadicArg, arg.Name, cc.GetName())
+			}
+		}
+		return
+	})
+}
+
+// parsePositionalArgs parses and validates remainArgs with the Args of
+// goCommand. All problems are reported in one error.
+func (w *ExecWorker) parsePositionalArgs(goCommand *Command, remainArgs []string) (err error) {
+	goCommand.argValues = make(map[string][]string)
+	goCommand.argTypedValues = make(map[string]interface{})
+	if len(goCommand.Args) == 0 {
+		return
+	}
+
+	var problems []string
+	i := 0
+	for _, arg := range goCommand.Args {
+		var texts []string
+		if arg.Variadic {
+			texts, i = remainArgs[i:], len(remainArgs)
+		} else if i < len(remainArgs) {
+			texts, i = remainArgs[i:i+1], i+1
+		}
+
+		if len(texts) == 0 {
+			if arg.Required {
+				problems = append(problems, fmt.Sprintf("missing <%v>", arg.Name))
+			}
+			continue
+		}
+
+		var values []interface{}
+		for _, s := range texts {
+			v, e := arg.convert(s)
+			if e != nil {
+				problems = append(problems, fmt.Sprintf("<%v>: %v", arg.Name, e))
+				continue
+			}
+			values = append(values, v)
+		}
+		goCommand.argValues[arg.Name] = texts
+		if arg.Variadic {
+			goCommand.argTypedValues[arg.Name] = arg.typedSlice(values)
+		} else if len(values) > 0 {
+			goCommand.argTypedValues[arg.Name] = values[0]
+		}
+	}
+	if i < len(remainArgs) {
+		problems = append(problems, fmt.Sprintf("unexpected %q", remainArgs[i:]))
+	}
+
+	if len(problems) > 0 {
+		err = newError(false, errWrongArgs, strings.Join(problems, ", "), goCommand.GetName())
+	}
+	return
+}
+
+// convert returns the value of s in the type of DefaultValue.
+func (a *PositionalArg) convert(s string) (v interface{}, err error) {
+	if len(a.ValidArgs) > 0 {
+		valid := false
+		for _, va := range a.ValidArgs {
+			valid = valid || va == s
+		}
+		if !valid {
+			err = fmt.Errorf("%q is not one of %v", s, a.ValidArgs)
+			return
+		}
+	}
+
+	return convertText(s, a.DefaultValue)
+}
+
+// convertText returns the value of s in the type of sample, a string
+// is returned if sample isn't a bool, number or time.Duration.
+func convertText(s string, sample interface{}) (v interface{}, err error) {
+	if _, ok := sample.(time.Duration); ok {
+		return time.ParseDuration(s)
+	}
+
+	var x interface{}
+	t := reflect.TypeOf(sample)
+	switch reflect.ValueOf(sample).Kind() {
+	case reflect.Bool:
+		x, err = strconv.ParseBool(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x, err = strconv.ParseInt(s, 0, t.Bits())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		x, err = strconv.ParseUint(s, 0, t.Bits())
+	case reflect.Float32, reflect.Float64:
+		x, err = strconv.ParseFloat(s, t.Bits())
+	default:
+		return s, nil
+	}
+	if err != nil {
+		err = fmt.Errorf("%q is not a valid %T", s, sample)
+		return
+	}
+	v = reflect.ValueOf(x).Convert(t).Interface()
+	return
+}
+
+// typedSlice returns values as a slice in the type of DefaultValue,
+// such as []int.
+func (a *PositionalArg) typedSlice(values []interface{}) interface{} {
+	typ := reflect.TypeOf("")
+	if a.DefaultValue != nil {
+		typ = reflect.TypeOf(a.DefaultValue)
+	}
+	slice := reflect.MakeSlice(reflect.SliceOf(typ), 0, len(values))
+	for _, v := range values {
+		slice = reflect.Append(slice, reflect.ValueOf(v))
+	}
+	return slice.Interface()
+}
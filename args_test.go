@@ -0,0 +1,62 @@
+// Copyright © 2020 Hedzr Yeh.
+
+package cmdr_test
+
+import (
+	"fmt"
+	"github.com/hedzr/cmdr"
+	"testing"
+	"time"
+)
+
+func TestPositionalArgs(t *testing.T) {
+	c := &cmdrTester{T: t}
+	c.newRoot = func() *cmdr.RootCommand {
+		root := cmdr.Root("positional", "1.0.0")
+		root.NewSubCommand("connect").
+			PositionalArgs(
+				&cmdr.PositionalArg{Name: "host", Required: true, Completer: func(cmd *cmdr.Command, partial string) []string {
+					return []string{"alpha", "beta"}
+				}},
+				&cmdr.PositionalArg{Name: "port", DefaultValue: uint16(22)},
+				&cmdr.PositionalArg{Name: "mode", DefaultValue: "tcp", ValidArgs: []string{"tcp", "udp"}},
+				&cmdr.PositionalArg{Name: "timeouts", DefaultValue: time.Second, Variadic: true},
+			).
+			Action(c.action)
+		return root.RootCommand()
+	}
+	c.result = func(w *cmdr.ExecWorker) string {
+		if c.cmd == nil {
+			return none
+		}
+		return fmt.Sprintf("%v %v %v %v", c.cmd.Arg("host"), c.cmd.ArgValue("port"), c.cmd.Arg("mode"), c.cmd.ArgValue("timeouts"))
+	}
+
+	c.expect(conformance{
+		{"connect h1", "h1 22 tcp []"},
+		{"connect h1 2222 udp", "h1 2222 udp []"},
+		{"connect h1 0x16 tcp 1s 2m", "h1 22 tcp [1s 2m0s]"},
+		{"connect", "invalid arguments: missing <host>, under command 'connect'"},
+		{"connect h1 ssh", `invalid arguments: <port>: "ssh" is not a valid uint16`},
+		{"connect h1 65536", `invalid arguments: <port>: "65536" is not a valid uint16`},
+		{"connect h1 22 sctp 1x", `<mode>: "sctp" is not one of [tcp udp], <timeouts>: time: unknown unit`},
+	})
+	c.expectOutput("connect --help", "connect <host> [port] [mode] [timeouts...]")
+	c.expectOutputs(conformance{
+		{"__complete connect ", "alpha\nbeta\n"},
+		{"__complete connect alpha 22 u", "udp\n"},
+	})
+
+	// only the last one can be variadic
+	c.newRoot = func() *cmdr.RootCommand {
+		root := cmdr.Root("args", "1.0.0")
+		root.NewSubCommand("copy").PositionalArgs(
+			&cmdr.PositionalArg{Name: "files", Variadic: true},
+			&cmdr.PositionalArg{Name: "dest", Required: true},
+		)
+		return root.RootCommand()
+	}
+	c.expect(conformance{
+		{"copy a b", "the variadic argument <files> must be the last one, under command 'copy'"},
+	})
+}
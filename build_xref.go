@@ -33,12 +33,15 @@ func (w *ExecWorker) setupFromEnvvarMap() {
 	}
 }
 
-func (w *ExecWorker) buildXref(rootCmd *RootCommand) (err error) {
+func (w *ExecWorker) buildXref(rootCmd *RootCommand, args []string) (err error) {
 	flog("--> preprocess / buildXref")
 
 	// build xref for root command and its all sub-commands and flags
 	// and build the default values
 	w.buildRootCrossRefs(rootCmd)
+	if err = checkArgsDecl(&rootCmd.Command); err != nil {
+		return
+	}
 
 	w.setupFromEnvvarMap()
 
@@ -49,7 +52,7 @@ func (w *ExecWorker) buildXref(rootCmd *RootCommand) (err error) {
 		//if err = w.parsePredefinedLocation(); err != nil {
 		//	return
 		//}
-		_ = w.parsePredefinedLocation()
+		_ = w.parsePredefinedLocation(args)
 
 		// and now, loading the external configuration files
 		err = w.loadFromPredefinedLocation(rootCmd)
@@ -59,17 +62,20 @@ func (w *ExecWorker) buildXref(rootCmd *RootCommand) (err error) {
 		// }
 		// w.envPrefixes = EnvPrefix
 		var envPrefix []string
-		eps := GetString("env-prefix", "")
+		eps := w.rxxtOptions.GetString(w.wrapWithRxxtPrefix("env-prefix"), "")
 		if eps != "" && strings.Trim(eps, "[]") == eps {
 			envPrefix = strings.Split(eps, ".")
 		} else {
-			envPrefix = GetStringSlice("env-prefix")
+			envPrefix = w.rxxtOptions.GetStringSlice(w.wrapWithRxxtPrefix("env-prefix"))
 		}
 		if len(envPrefix) > 0 {
 			w.envPrefixes = envPrefix
 			flog("--> preprocess / buildXref: env-prefix %v loaded", envPrefix)
 		}
 	}
+
+	// the aliases in the config files
+	w.buildAliases(rootCmd)
 	return
 }
 
@@ -87,7 +93,10 @@ func (w *ExecWorker) buildRootCrossRefs(root *RootCommand) {
 	w.attachHelpCommands(root)
 	w.attachVerboseCommands(root)
 	w.attachGeneratorsCommands(root)
+	w.attachCompletionCommands(root)
 	w.attachCmdrCommands(root)
+	w.attachAliasCommands(root)
+	w.attachShellCommand(root)
 
 	w.buildCrossRefs(&root.Command)
 }
@@ -148,8 +157,9 @@ func (w *ExecWorker) attachVersionCommands(root *RootCommand) {
 					Group:       SysMgmtGroup,
 					owner:       &root.Command,
 					Action: func(cmd *Command, args []string) (err error) {
-						conf.Version = GetStringR("version-sim")
-						Set("version", conf.Version) // set into option 'app.version' too.
+						w := cmd.worker()
+						conf.Version = w.rxxtOptions.GetString(w.wrapWithRxxtPrefix("version-sim"))
+						w.rxxtOptions.Set("version", conf.Version) // set into option 'app.version' too.
 						return
 					},
 				},
@@ -495,29 +505,54 @@ func (w *ExecWorker) attachGeneratorsCommands(root *RootCommand) {
 			}
 		}
 		if !found {
-			root.SubCommands = append(root.SubCommands, generatorCommands)
+			root.SubCommands = append(root.SubCommands, cloneCommand(generatorCommands))
 		}
 	}
 }
 
+// cloneCommand returns a deep copy of the builtin command cmd, with its
+// flags and sub-commands, so that the states changed while parsing,
+// such as Flag.times, are never shared between the workers.
+func cloneCommand(cmd *Command) *Command {
+	cc := *cmd
+	cc.owner, cc.root, cc.headLikeFlag = nil, nil, nil
+	cc.allCmds, cc.allFlags, cc.plainCmds = nil, nil, nil
+	cc.plainShortFlags, cc.plainLongFlags = nil, nil
+	cc.argValues, cc.argTypedValues = nil, nil
+
+	cc.Flags = make([]*Flag, 0, len(cmd.Flags))
+	for _, flg := range cmd.Flags {
+		f := *flg
+		f.owner, f.times = &cc, 0
+		cc.Flags = append(cc.Flags, &f)
+	}
+	cc.SubCommands = make([]*Command, 0, len(cmd.SubCommands))
+	for _, sc := range cmd.SubCommands {
+		cx := cloneCommand(sc)
+		cx.owner = &cc
+		cc.SubCommands = append(cc.SubCommands, cx)
+	}
+	return &cc
+}
+
 func (w *ExecWorker) forFlagNames(flg *Flag, cmd *Command, singleFlagNames, stringFlagNames map[string]bool) {
 	if len(flg.Short) != 0 {
 		if _, ok := singleFlagNames[flg.Short]; ok {
-			ferr("\nNOTE: flag char '%v' has been used. (command: %v)", flg.Short, w.backtraceCmdNames(cmd))
+			w.ferr("\nNOTE: flag char '%v' has been used. (command: %v)", flg.Short, w.backtraceCmdNames(cmd))
 		} else {
 			singleFlagNames[flg.Short] = true
 		}
 	}
 	if len(flg.Full) != 0 {
 		if _, ok := stringFlagNames[flg.Full]; ok {
-			ferr("\nNOTE: flag '%v' has been used. (command: %v)", flg.Full, w.backtraceCmdNames(cmd))
+			w.ferr("\nNOTE: flag '%v' has been used. (command: %v)", flg.Full, w.backtraceCmdNames(cmd))
 		} else {
 			stringFlagNames[flg.Full] = true
 		}
 	}
 	if len(flg.Short) == 0 && len(flg.Full) == 0 && len(flg.Name) != 0 {
 		if _, ok := stringFlagNames[flg.Name]; ok {
-			ferr("\nNOTE: flag '%v' has been used. (command: %v)", flg.Name, w.backtraceCmdNames(cmd))
+			w.ferr("\nNOTE: flag '%v' has been used. (command: %v)", flg.Name, w.backtraceCmdNames(cmd))
 		} else {
 			stringFlagNames[flg.Name] = true
 		}
@@ -529,7 +564,7 @@ func (w *ExecWorker) buildCrossRefsForFlag(flg *Flag, cmd *Command, singleFlagNa
 
 	for _, sz := range flg.Aliases {
 		if _, ok := stringFlagNames[sz]; ok {
-			ferr("\nNOTE: flag alias name '%v' has been used. (command: %v)", sz, w.backtraceCmdNames(cmd))
+			w.ferr("\nNOTE: flag alias name '%v' has been used. (command: %v)", sz, w.backtraceCmdNames(cmd))
 		} else {
 			stringFlagNames[sz] = true
 		}
@@ -555,21 +590,21 @@ func (w *ExecWorker) buildCrossRefsForFlag(flg *Flag, cmd *Command, singleFlagNa
 func (w *ExecWorker) forCommandNames(cx, cmd *Command, singleCmdNames, stringCmdNames map[string]bool) {
 	if len(cx.Short) != 0 {
 		if _, ok := singleCmdNames[cx.Short]; ok {
-			ferr("\nNOTE: command char '%v' has been used. (command: %v)", cx.Short, w.backtraceCmdNames(cmd))
+			w.ferr("\nNOTE: command char '%v' has been used. (command: %v)", cx.Short, w.backtraceCmdNames(cmd))
 		} else {
 			singleCmdNames[cx.Short] = true
 		}
 	}
 	if len(cx.Full) != 0 {
 		if _, ok := stringCmdNames[cx.Full]; ok {
-			ferr("\nNOTE: command '%v' has been used. (command: %v)", cx.Full, w.backtraceCmdNames(cmd))
+			w.ferr("\nNOTE: command '%v' has been used. (command: %v)", cx.Full, w.backtraceCmdNames(cmd))
 		} else {
 			stringCmdNames[cx.Full] = true
 		}
 	}
 	if len(cx.Short) == 0 && len(cx.Full) == 0 && len(cx.Name) != 0 {
 		if _, ok := stringCmdNames[cx.Name]; ok {
-			ferr("\nNOTE: command '%v' has been used. (command: %v)", cx.Name, w.backtraceCmdNames(cmd))
+			w.ferr("\nNOTE: command '%v' has been used. (command: %v)", cx.Name, w.backtraceCmdNames(cmd))
 		} else {
 			stringCmdNames[cx.Name] = true
 		}
@@ -583,7 +618,7 @@ func (w *ExecWorker) buildCrossRefsForCommand(cx, cmd *Command, singleCmdNames,
 	for _, sz := range cx.Aliases {
 		if len(sz) != 0 {
 			if _, ok := stringCmdNames[sz]; ok {
-				ferr("\nNOTE: command alias name '%v' has been used. (command: %v)", sz, w.backtraceCmdNames(cmd))
+				w.ferr("\nNOTE: command alias name '%v' has been used. (command: %v)", sz, w.backtraceCmdNames(cmd))
 			} else {
 				stringCmdNames[sz] = true
 			}
@@ -625,11 +660,31 @@ func (w *ExecWorker) buildCrossRefs(cmd *Command) {
 			ph := strings.ToUpper(strings.Trim(string(b), "`"))
 			flg.DefaultValuePlaceholder = ph
 		}
+		flg.value = toValue(flg.DefaultValue, flg.value)
+		if flg.value != nil && len(flg.DefaultValuePlaceholder) == 0 {
+			flg.DefaultValuePlaceholder = strings.ToUpper(flg.value.Type())
+		}
 
 		w.buildCrossRefsForFlag(flg, cmd, singleFlagNames, stringFlagNames)
 
 		// opt.Children[flg.Full] = &OptOne{Value: flg.DefaultValue,}
-		w.rxxtOptions.Set(w.backtraceFlagNames(flg), flg.DefaultValue)
+		if flg.isMap() {
+			w.rxxtOptions.setMap(w.wrapWithRxxtPrefix(w.backtraceFlagNames(flg)), toStringKeyMap(flg.DefaultValue), false)
+		} else if flg.value != nil {
+			w.rxxtOptions.Set(w.backtraceFlagNames(flg), flg.value)
+		} else {
+			w.rxxtOptions.Set(w.backtraceFlagNames(flg), flg.DefaultValue)
+		}
+	}
+
+	// the negated forms take precedence over the flags in the same
+	// names, such as the builtin `--no-color`, see also setNegatedPeers
+	for _, flg := range cmd.Flags {
+		if flg.Negatable && isBool(flg.DefaultValue) {
+			for _, sz := range flg.GetLongTitleNamesArray() {
+				cmd.plainLongFlags["no-"+sz] = flg
+			}
+		}
 	}
 
 	for _, cx := range cmd.SubCommands {
@@ -18,17 +18,31 @@ func (c *RootCommand) AppendPostActions(fns ...func(cmd *Command, args []string)
 
 // PrintHelp prints help screen
 func (c *Command) PrintHelp(justFlags bool) {
-	internalGetWorker().printHelp(c, justFlags)
+	c.worker().printHelp(c, justFlags)
 }
 
 // PrintVersion prints versions information
 func (c *Command) PrintVersion() {
-	internalGetWorker().showVersion()
+	c.worker().showVersion()
 }
 
 // PrintBuildInfo print building information
 func (c *Command) PrintBuildInfo() {
-	internalGetWorker().showBuildInfo()
+	c.worker().showBuildInfo()
+}
+
+// worker returns the ExecWorker which is running this command tree.
+// The builtin commands (such as `generate`) might be shared by
+// several trees, so we look up the owners chain but not `c.root`.
+func (c *Command) worker() *ExecWorker {
+	p := c
+	for p.owner != nil {
+		p = p.owner
+	}
+	if p.root != nil && p.root.w != nil {
+		return p.root.w
+	}
+	return internalGetWorker()
 }
 
 // GetRoot returns the `RootCommand`
@@ -136,7 +150,7 @@ func (c *Command) GetName() string {
 // more information about Option Prefix, refer
 // to [WithOptionsPrefix]
 func (c *Command) GetDottedNamePath() string {
-	return internalGetWorker().backtraceCmdNames(c)
+	return c.worker().backtraceCmdNames(c)
 }
 
 // GetQuotedGroupName returns the group name quoted string.
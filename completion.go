@@ -0,0 +1,215 @@
+// Copyright © 2020 Hedzr Yeh.
+
+package cmdr
+
+import (
+	"sort"
+	"strings"
+)
+
+// completeCommandName is the name of the hidden command which prints
+// the completion candidates for the shell completion scripts:
+//
+//     app __complete server --level ""
+//
+// The last argument is the partial word under the cursor, it might be
+// an empty string. Each candidate is printed in one line, optionally
+// followed by a tab char and its description.
+const completeCommandName = "__complete"
+
+type (
+	// Completer returns the dynamic completion candidates for a flag
+	// value, or for the positional arguments of a command, such as the
+	// hostnames or the resource IDs.
+	//
+	// cmd is the matched command, partial is the word being completed.
+	// The candidates which don't match the partial will be filtered out.
+	Completer func(cmd *Command, partial string) []string
+)
+
+func (w *ExecWorker) attachCompletionCommands(root *RootCommand) {
+	if w.enableGenerateCommands {
+		if _, ok := root.allCmds[SysMgmtGroup][completeCommandName]; !ok {
+			cx := &Command{
+				BaseOpt: BaseOpt{
+					Full:        completeCommandName,
+					Description: "print the completion candidates for a partial command-line",
+					Hidden:      true,
+					Group:       SysMgmtGroup,
+					owner:       &root.Command,
+					Action: func(cmd *Command, args []string) (err error) {
+						w := cmd.worker()
+						for _, c := range w.completionCandidates(w.rootCommand, args) {
+							w.fp("%v", c)
+						}
+						return
+					},
+				},
+				TailPlaceHolder: "[words...] <partial>",
+			}
+			root.SubCommands = uniAddCmd(root.SubCommands, cx)
+			root.allCmds[SysMgmtGroup][completeCommandName] = cx
+			root.plainCmds[completeCommandName] = cx
+		}
+	}
+}
+
+// completeArgs invokes the hidden `__complete` command with the raw
+// args, without parsing them.
+func (w *ExecWorker) completeArgs(rootCmd *RootCommand, args []string) (last *Command, ok bool, err error) {
+	if len(args) > 1 && args[1] == completeCommandName {
+		if last, ok = rootCmd.plainCmds[completeCommandName]; ok {
+			err = last.Action(last, args[2:])
+		}
+	}
+	return
+}
+
+// completionCandidates returns the candidates for words, which are the
+// args after the app name; the last one is the partial word.
+func (w *ExecWorker) completionCandidates(root *RootCommand, words []string) (candidates []string) {
+	var partial string
+	if len(words) > 0 {
+		partial = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+
+	cmd := &root.Command
+	var valueFlag *Flag
+	var positional int // the count of the positional args of cmd
+	for _, word := range words {
+		if valueFlag != nil {
+			valueFlag = nil
+			continue
+		}
+		if strings.HasPrefix(word, "-") && len(word) > 1 {
+			if flg := lookupCompletionFlag(cmd, word); flg != nil && flg.takesNextArg() && !strings.Contains(word, "=") {
+				valueFlag = flg
+			}
+			continue
+		}
+		if cc, ok := cmd.plainCmds[word]; ok && positional == 0 {
+			cmd = cc
+		} else {
+			positional++
+		}
+	}
+
+	if valueFlag != nil {
+		return flagValueCandidates(cmd, valueFlag, partial, "")
+	}
+
+	if strings.HasPrefix(partial, "-") {
+		if i := strings.Index(partial, "="); i > 0 {
+			if flg := lookupCompletionFlag(cmd, partial[:i]); flg != nil {
+				return flagValueCandidates(cmd, flg, partial[i+1:], partial[:i+1])
+			}
+			return
+		}
+		for _, flg := range visibleFlagsInChain(cmd) {
+			for _, name := range dashedFlagNames(flg) {
+				if strings.HasPrefix(name, partial) {
+					candidates = append(candidates, completionCandidate(name, flg.Description))
+				}
+			}
+		}
+		return
+	}
+
+	for _, cc := range cmd.SubCommands {
+		if cc.Hidden {
+			continue
+		}
+		for _, name := range cc.GetTitleNamesArray() {
+			if strings.HasPrefix(name, partial) {
+				candidates = append(candidates, completionCandidate(name, cc.Description))
+			}
+		}
+	}
+	if arg := cmd.argAt(positional); arg != nil {
+		if arg.Completer != nil {
+			candidates = append(candidates, filterByPrefix(arg.Completer(cmd, partial), partial, "")...)
+		} else {
+			candidates = append(candidates, filterByPrefix(arg.ValidArgs, partial, "")...)
+		}
+	}
+	if cmd.Completer != nil {
+		candidates = append(candidates, filterByPrefix(cmd.Completer(cmd, partial), partial, "")...)
+	}
+	return
+}
+
+// lookupCompletionFlag finds the flag for a dashed word, such as "-v",
+// "--verbose" or "--level=debug", in cmd and its parents.
+func lookupCompletionFlag(cmd *Command, word string) *Flag {
+	if i := strings.Index(word, "="); i > 0 {
+		word = word[:i]
+	}
+	for c := cmd; c != nil; c = c.owner {
+		if strings.HasPrefix(word, "--") {
+			if flg, ok := c.plainLongFlags[word[2:]]; ok {
+				return flg
+			}
+		} else if flg, ok := c.plainShortFlags[word[1:]]; ok {
+			return flg
+		}
+	}
+	return nil
+}
+
+func flagValueCandidates(cmd *Command, flg *Flag, partial, prefix string) []string {
+	if flg.Completer != nil {
+		return filterByPrefix(flg.Completer(cmd, partial), partial, prefix)
+	}
+	return filterByPrefix(flg.ValidArgs, partial, prefix)
+}
+
+func filterByPrefix(list []string, partial, prefix string) (ret []string) {
+	for _, s := range list {
+		if strings.HasPrefix(s, partial) {
+			ret = append(ret, prefix+s)
+		}
+	}
+	return
+}
+
+func completionCandidate(name, desc string) string {
+	if desc = strings.Join(strings.Fields(desc), " "); desc != "" {
+		return name + "\t" + desc
+	}
+	return name
+}
+
+// visibleFlagsInChain returns the visible flags of cmd and its parents,
+// sorted by their titles.
+func visibleFlagsInChain(cmd *Command) (flags []*Flag) {
+	seen := make(map[string]bool)
+	for c := cmd; c != nil; c = c.owner {
+		for _, flg := range c.Flags {
+			if flg.Hidden || seen[flg.GetTitleName()] {
+				continue
+			}
+			seen[flg.GetTitleName()] = true
+			flags = append(flags, flg)
+		}
+	}
+	sort.SliceStable(flags, func(i, j int) bool {
+		return flags[i].GetTitleName() < flags[j].GetTitleName()
+	})
+	return
+}
+
+// dashedFlagNames returns the dashed names of flg, such as "-v", "--verbose",
+// and "--no-color" for a Negatable flag.
+func dashedFlagNames(flg *Flag) (names []string) {
+	for _, s := range flg.GetShortTitleNamesArray() {
+		names = append(names, "-"+s)
+	}
+	for _, s := range flg.GetLongTitleNamesArray() {
+		names = append(names, "--"+s)
+		if flg.Negatable {
+			names = append(names, "--no-"+s)
+		}
+	}
+	return
+}
@@ -0,0 +1,126 @@
+// Copyright © 2020 Hedzr Yeh.
+
+package cmdr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// checkFlagConstraints checks Command.Exclusive, Command.Requires and
+// Command.ConflictsWith of goCommand and its parents, so they are
+// inherited by the sub-commands. All violations are reported in one
+// error.
+func (w *ExecWorker) checkFlagConstraints(goCommand *Command) (err error) {
+	var problems []string
+	for cmd := goCommand; cmd != nil; cmd = cmd.owner {
+		for _, group := range cmd.Exclusive {
+			var given []string
+			for _, flg := range findFlagsInChain(cmd, group) {
+				if flg.times > 0 {
+					given = append(given, flg.GetTitleZshFlagName())
+				}
+			}
+			if len(given) > 1 {
+				problems = append(problems, fmt.Sprintf("%v are mutually exclusive", strings.Join(given, " and ")))
+			}
+		}
+
+		for _, item := range cmd.Requires {
+			if len(item) == 0 {
+				continue
+			}
+			if flg := findFlagInChain(cmd, item[0]); flg != nil && w.flagGiven(flg) {
+				for _, f := range findFlagsInChain(cmd, item[1:]) {
+					if !w.flagGiven(f) {
+						problems = append(problems, fmt.Sprintf("%v requires %v", flg.GetTitleZshFlagName(), f.GetTitleZshFlagName()))
+					}
+				}
+			}
+		}
+
+		for _, item := range cmd.ConflictsWith {
+			if len(item) == 0 {
+				continue
+			}
+			if flg := findFlagInChain(cmd, item[0]); flg != nil && flg.times > 0 {
+				for _, f := range findFlagsInChain(cmd, item[1:]) {
+					if f.times > 0 {
+						problems = append(problems, fmt.Sprintf("%v conflicts with %v", flg.GetTitleZshFlagName(), f.GetTitleZshFlagName()))
+					}
+				}
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		err = newError(false, errFlagsConflicted, strings.Join(problems, ", "), goCommand.GetName())
+	}
+	return
+}
+
+// findFlagsInChain finds the flags by their long titles in cmd and its
+// parents, the unknown ones are ignored.
+func findFlagsInChain(cmd *Command, fulls []string) (flags []*Flag) {
+	for _, full := range fulls {
+		if flg := findFlagInChain(cmd, full); flg != nil {
+			flags = append(flags, flg)
+		}
+	}
+	return
+}
+
+// flagConstraintNotes returns the notes about the constraints on flg,
+// which are declared in cmd and its parents, for the help screen. Such
+// as: "excludes: --yaml", "requires: --tls-key".
+func flagConstraintNotes(cmd *Command, flg *Flag) (notes []string) {
+	names := func(fulls []string) string {
+		var a []string
+		for _, f := range findFlagsInChain(cmd, fulls) {
+			if f != flg {
+				a = append(a, f.GetTitleZshFlagName())
+			}
+		}
+		return strings.Join(a, ", ")
+	}
+	note := func(title string, fulls []string) {
+		if s := names(fulls); s != "" {
+			notes = append(notes, title+": "+s)
+		}
+	}
+
+	for c := cmd; c != nil; c = c.owner {
+		for _, group := range c.Exclusive {
+			if contains(group, flg.Full) {
+				note("excludes", group)
+			}
+		}
+		for _, item := range c.Requires {
+			if len(item) > 0 && item[0] == flg.Full {
+				note("requires", item[1:])
+			}
+		}
+		for _, item := range c.ConflictsWith {
+			if len(item) == 0 {
+				continue
+			}
+			if item[0] == flg.Full {
+				note("conflicts with", item[1:])
+			} else if contains(item[1:], flg.Full) {
+				note("conflicts with", item[:1])
+			}
+		}
+	}
+	// the grouped requirements are not inherited, see checkRequiredArgs
+	for _, group := range cmd.RequiredTogether {
+		if contains(group, flg.Full) {
+			note("together with", group)
+		}
+	}
+	for _, group := range cmd.AtLeastOneOf {
+		if contains(group, flg.Full) {
+			note("or", group)
+		}
+	}
+	return
+}
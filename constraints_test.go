@@ -0,0 +1,41 @@
+// Copyright © 2020 Hedzr Yeh.
+
+package cmdr_test
+
+import (
+	"github.com/hedzr/cmdr"
+	"testing"
+)
+
+func TestFlagConstraints(t *testing.T) {
+	newRoot := func() *cmdr.RootCommand {
+		root := cmdr.Root("constrained", "1.0.0")
+		cmdr.NewBool(false).Titles("json", "").AttachTo(root)
+		cmdr.NewBool(false).Titles("yaml", "").AttachTo(root)
+		root.Exclusive("json", "yaml")
+		serve := root.NewSubCommand("serve").
+			Action(func(cmd *cmdr.Command, args []string) (err error) { return }).
+			Requires("tls-cert", "tls-key").
+			ConflictsWith("insecure", "tls-cert", "tls-key")
+		cmdr.NewString("").Titles("tls-cert", "").AttachTo(serve)
+		cmdr.NewString("").Titles("tls-key", "").EnvKeys("CONSTRAINED_TLS_KEY").AttachTo(serve)
+		cmdr.NewBool(false).Titles("insecure", "k").AttachTo(serve)
+		return root.RootCommand()
+	}
+
+	c := &cmdrTester{T: t, newRoot: newRoot}
+	c.expect(conformance{
+		{"serve --json", ""},
+		{"serve --yaml -k", ""},
+		{"serve --tls-cert c --tls-key k", ""},
+		{"serve --json --yaml", "invalid flags: --json and --yaml are mutually exclusive, under command 'serve'"},
+		{"serve --tls-cert c", "invalid flags: --tls-cert requires --tls-key"},
+		{"serve -k --tls-cert c --tls-key k", "--insecure conflicts with --tls-cert, --insecure conflicts with --tls-key"},
+		{"serve --json --yaml -k --tls-cert c", "--tls-cert requires --tls-key, --insecure conflicts with --tls-cert, --json and --yaml are mutually exclusive"},
+	})
+	// the requirement is satisfied by the environment variables
+	c.expectEnv("CONSTRAINED_TLS_KEY", "k", conformance{
+		{"serve --tls-cert c", ""},
+	})
+	c.expectOutput("serve --help", "[requires: --tls-key]", "[conflicts with: --tls-cert, --tls-key]", "[conflicts with: --insecure]", "[excludes: --yaml]")
+}
@@ -16,7 +16,19 @@ var (
 	// ErrBadArg is a generic error for user
 	ErrBadArg = newErrorWithMsg("bad argument")
 
-	errWrongEnumValue = newErrTmpl("unexpected enumerable value '%s' for option '%s', under command '%s'")
+	errWrongEnumValue      = newErrTmpl("unexpected enumerable value '%s' for option '%s', under command '%s'")
+	errRequiredFlagsMissed = newErrTmpl("required flags missed: %s, under command '%s'")
+	errFlagsConflicted     = newErrTmpl("invalid flags: %s, under command '%s'")
+	errWrongArgs           = newErrTmpl("invalid arguments: %s, under command '%s'")
+	errVariadicArg         = newErrTmpl("the variadic argument <%s> must be the last one, under command '%s'")
+	errValueOutOfRange     = newErrTmpl("value '%v' for option '%s' is out of range %s (from %s), under command '%s'")
+	errWrongValue          = newErrTmpl("invalid %s value '%s' for option '%s' (from %s): %v, under command '%s'")
+	errFlagValueMissed     = newErrTmpl("missing value for option '%s', under command '%s'")
+	errResponseFile        = newErrTmpl("cannot expand the response file '%s': %v")
+	errAmbiguousCmd        = newErrTmpl("ambiguous command '%s', could be: %s, under command '%s'")
+	errAmbiguousFlag       = newErrTmpl("ambiguous option '%s', could be: %s, under command '%s'")
+	errAliasLoop           = newErrTmpl("the alias '%s' expands into itself, under command '%s'")
+	errAliasSave           = newErrTmpl("cannot save the alias '%s': %v")
 )
 
 // ErrorForCmdr structure
@@ -27,6 +39,7 @@ type ErrorForCmdr struct {
 	causer    error
 	msg       string
 	livedArgs []interface{}
+	printed   bool // printed by the parser already, see runShell
 }
 
 // newError formats a ErrorForCmdr object
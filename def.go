@@ -18,6 +18,10 @@ const (
 	UnsortedGroup = "zzzz.unsorted"
 	// SysMgmtGroup for commands and flags
 	SysMgmtGroup = "zzz9.Misc"
+	// PluginsGroup for the plugins, see WithPlugins
+	PluginsGroup = "zzz8.Plugins"
+	// AliasesGroup for the aliases in the config file, see WithAliasCommands
+	AliasesGroup = "zzz7.Aliases"
 
 	// DefaultEditor is 'vim'
 	DefaultEditor = "vim"
@@ -80,6 +84,40 @@ type (
 		// be shown at tail of command usages line. Such as for TailPlaceHolder="<host-fqdn> <ipv4/6>":
 		// austr dns add <host-fqdn> <ipv4/6> [Options] [Parent/Global Options]
 		TailPlaceHolder string
+		// Completer returns the dynamic candidates of the positional
+		// arguments for the shell completions.
+		Completer Completer
+		// Args declares the positional arguments. They will be parsed
+		// and validated before invoking Action, and can be read by
+		// Command.Arg(name), Command.ArgValue(name).
+		// If TailPlaceHolder is empty, it will be built from Args.
+		Args []*PositionalArg
+		// RequiredTogether lists the groups of flags, by their long
+		// titles. If one flag of a group is given, the others of the
+		// group are required too, such as: {"cert", "key"}.
+		// The groups are checked only if this command is invoked, but
+		// not for its sub-commands.
+		RequiredTogether [][]string
+		// AtLeastOneOf lists the groups of flags, by their long titles.
+		// At least one flag of each group is required, such as:
+		// {"token", "password"}.
+		// The groups are checked only if this command is invoked, but
+		// not for its sub-commands.
+		AtLeastOneOf [][]string
+		// Exclusive lists the groups of mutually exclusive flags, by
+		// their long titles, such as: {"json", "yaml"}.
+		// Only the flags given in command-line are checked.
+		// Exclusive, Requires and ConflictsWith are inherited by the
+		// sub-commands.
+		Exclusive [][]string
+		// Requires lists the dependencies of flags, by their long
+		// titles. The first flag of each item requires all the others,
+		// such as: {"tls-cert", "tls-key"}.
+		Requires [][]string
+		// ConflictsWith lists the conflicts of flags, by their long
+		// titles. The first flag of each item conflicts with all the
+		// others. Only the flags given in command-line are checked.
+		ConflictsWith [][]string
 		// TailArgsText string
 		// TailArgsDesc string
 
@@ -90,6 +128,9 @@ type (
 		plainShortFlags map[string]*Flag
 		plainLongFlags  map[string]*Flag
 		headLikeFlag    *Flag
+		argValues       map[string][]string    // the raw texts of Args
+		argTypedValues  map[string]interface{} // the typed values of Args
+		alias           []string               // the args of an alias command, see buildAliases
 	}
 
 	// RootCommand holds some application information
@@ -109,6 +150,26 @@ type (
 
 		ow   *bufio.Writer
 		oerr *bufio.Writer
+		w    *ExecWorker
+	}
+
+	// PositionalArg declares a positional argument of a command.
+	PositionalArg struct {
+		// Name is shown in usage line as <name>, and used by Command.Arg(name)
+		Name        string
+		Description string
+		// DefaultValue is the value if the argument is absent, and its
+		// type is the type of the argument, such as: "", 0, 1.0, true,
+		// time.Second. nil means a string.
+		DefaultValue interface{}
+		// Required argument must be present.
+		Required bool
+		// Variadic takes all the remained arguments, it must be the last one.
+		Variadic bool
+		// ValidArgs for enum type, the value must be one of them.
+		ValidArgs []string
+		// Completer returns the dynamic candidates for the shell completions.
+		Completer Completer
 	}
 
 	// Flag means a flag, a option, or a opt.
@@ -119,11 +180,20 @@ type (
 		ToggleGroup string
 		// DefaultValuePlaceholder for flag
 		DefaultValuePlaceholder string
-		// DefaultValue default value for flag
+		// DefaultValue default value for flag, its type is the type of
+		// the flag. time.Time, net.IP, *net.IPNet and *url.URL are
+		// supported, and a pointer to Value is used for the custom type.
 		DefaultValue interface{}
 		// ValidArgs for enum flag
 		ValidArgs []string
-		// Required to-do
+		// FileCompletion marks the value of this flag as a file path,
+		// so the shell completion scripts will complete it with file names.
+		FileCompletion bool
+		// Completer returns the dynamic candidates of the flag value for
+		// the shell completions, such as the hostnames or the resource IDs.
+		Completer Completer
+		// Required flag must be given a value, from command-line,
+		// environment variables or config files.
 		Required bool
 
 		// ExternalTool to get the value text by invoking external tool.
@@ -146,13 +216,37 @@ type (
 		// NOTE: Only one head-like option can be defined in a command/sub-command chain.
 		HeadLike bool
 
+		// Counter makes an int flag count how many times it's given,
+		// such as `-vvv` for 3. A value can be attached to set the count
+		// directly, such as `--verbose=3`, or from the environment
+		// variables, such as `VERBOSE=2`.
+		Counter bool
+
+		// Negatable makes a bool flag accept the negated forms of its
+		// long names, such as `--no-color` for `--color`, which set it
+		// to false. It's shown as `--[no-]color` in help screen.
+		Negatable bool
+
+		// NoOptDefVal makes the value of a flag optional, such as
+		// `--color[=WHEN]`: the flag takes NoOptDefVal if it's given
+		// bare, such as `--color`, and a value must be attached, such as
+		// `--color=always`, the next argument is never taken.
+		NoOptDefVal string
+
 		// Min minimal value of a range.
+		//
+		// The range [Min..Max] is validated for the numeric flags, no
+		// matter the value comes from command-line, environment variables
+		// or config files. It's ignored if Min >= Max.
 		Min int64
 		// Max maximal value of a range.
 		Max int64
 
 		onSet func(keyPath string, value interface{})
 
+		// value is the Value of DefaultValue, see toValue
+		value Value
+
 		// times how many times this flag was triggered.
 		// To access it with `Flag.GetTriggeredTimes()`.
 		times int
@@ -172,12 +266,16 @@ type (
 		usedConfigFile   string
 		usedConfigSubDir string
 		configFiles      []string
+		// configKeys are the keys loaded from the config files
+		configKeys map[string]bool
 
 		onConfigReloadedFunctions map[ConfigReloaded]bool
 		rwlCfgReload              *sync.RWMutex
 		rwCB                      sync.RWMutex
 		onMergingSet              func(keyPath string, value, oldVal interface{})
 		onSet                     func(keyPath string, value, oldVal interface{})
+
+		w *ExecWorker
 	}
 
 	// OptOne struct {
@@ -190,6 +288,26 @@ type (
 		OnConfigReloaded()
 	}
 
+	// Value is the interface to a custom value type of flag, such as an
+	// IP address, a CIDR, an URL or a semantic version. Use a pointer to
+	// it as the Flag.DefaultValue, just like the stdlib flag.Value.
+	//
+	// The default value is copied before Set is called, so Set may
+	// keep the state of the repeated occurrences of a flag in the
+	// command-line. The copied Value is stored in Options, retrieve it by
+	// cmdr.GetR(key).
+	Value interface {
+		// String returns the text of the value, it is shown as the
+		// default value in help screen.
+		String() string
+		// Set parses and sets the value from the text of command-line,
+		// environment variables or config files.
+		Set(text string) error
+		// Type returns the name of the value type, such as "ip", it is
+		// shown as the placeholder in help screen.
+		Type() string
+	}
+
 	// HookFunc the hook function prototype for SetBeforeXrefBuilding and SetAfterXrefBuilt
 	HookFunc func(root *RootCommand, args []string)
 
@@ -420,11 +420,15 @@ func TestForGenerateCommands(t *testing.T) {
 		"consul-tags gen doc --docx",
 		"consul-tags gen shell --bash",
 		"consul-tags gen shell --zsh",
+		"consul-tags gen shell --fish",
+		"consul-tags gen shell --powershell",
 		"consul-tags gen shell",
 	}
 	for _, cc := range commands {
 		cmdr.Set("generate.shell.zsh", false)
 		cmdr.Set("generate.shell.bash", false)
+		cmdr.Set("generate.shell.fish", false)
+		cmdr.Set("generate.shell.powershell", false)
 		cmdr.Set("generate.shell.auto", false)
 		cmdr.Set("generate.shell.force-bash", false)
 		cmdr.Set("generate.doc.pdf", false)
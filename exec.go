@@ -6,10 +6,15 @@ package cmdr
 
 import (
 	"bufio"
+	"fmt"
 	"github.com/hedzr/logex"
 	"gopkg.in/hedzr/errors.v2"
+	"io"
 	"os"
+	"reflect"
+	"strings"
 	"sync"
+	"time"
 )
 
 //
@@ -32,6 +37,10 @@ type ExecWorker struct {
 	enableVerboseCommands  bool
 	enableCmdrCommands     bool
 	enableGenerateCommands bool
+	enableAliasCommands    bool
+	enableShellCommand     bool
+	promptRequired         bool
+	inShell                bool
 
 	watchMainConfigFileToo   bool
 	doNotLoadingConfigFiles  bool
@@ -42,6 +51,8 @@ type ExecWorker struct {
 
 	currentHelpPainter Painter
 
+	defaultStdin  io.Reader
+	stdinReader   *bufio.Reader // the lines of defaultStdin, see stdinLine
 	defaultStdout *bufio.Writer
 	defaultStderr *bufio.Writer
 	closers       []func()
@@ -58,6 +69,11 @@ type ExecWorker struct {
 	noColor             bool
 	noEnvOverrides      bool
 	strictMode          bool
+	parsingMode         ParsingMode
+	responseFiles       bool
+	abbreviations       bool
+	plugins             bool
+	pluginDirs          []string
 	noUnknownCmdTip     bool
 	noCommandAction     bool
 
@@ -73,6 +89,9 @@ type ExecWorker struct {
 
 	onSwitchCharHit   func(parsed *Command, switchChar string, args []string) (err error)
 	onPassThruCharHit func(parsed *Command, switchChar string, args []string) (err error)
+
+	unknownOptionHandler  UnknownOptionHandler
+	unhandledErrorHandler UnhandledErrorHandler
 }
 
 // ExecOption is the functional option for Exec()
@@ -111,6 +130,52 @@ func Exec(rootCmd *RootCommand, opts ...ExecOption) (err error) {
 	return
 }
 
+// NewWorker returns a standalone ExecWorker bound to rootCmd.
+//
+// A worker made by NewWorker holds its own Options store, hooks, output
+// streams and root command, so that several command trees can be run
+// in one process, and the testings can be run in parallel:
+//
+//     w := cmdr.NewWorker(rootCmd, cmdr.WithNoLoadConfigFiles(true))
+//     if err := w.Run(os.Args); err != nil {
+//         log.Fatal(err)
+//     }
+//     fmt.Println(w.GetOptions().GetBoolEx(w.WrapWithRxxtPrefix("debug")))
+//
+// The package-level functions, such as Exec, Get*, Set, are still
+// working on the default worker.
+func NewWorker(rootCmd *RootCommand, opts ...ExecOption) (w *ExecWorker) {
+	w = newWorker()
+	for _, opt := range opts {
+		opt(w)
+	}
+	w.setupRootCommand(rootCmd)
+	return
+}
+
+// Run parses the command-line args (args[0] is the program name)
+// and invokes the matched command with this worker.
+func (w *ExecWorker) Run(args []string) (err error) {
+	defer func() {
+		for _, c := range w.closers {
+			c()
+		}
+	}()
+
+	_, err = w.InternalExecFor(w.rootCommand, args)
+	return
+}
+
+// GetOptions returns the Options store of this worker
+func (w *ExecWorker) GetOptions() *Options {
+	return w.rxxtOptions
+}
+
+// WrapWithRxxtPrefix wrap an key with the [RxxtPrefix] of this worker
+func (w *ExecWorker) WrapWithRxxtPrefix(key string) string {
+	return w.wrapWithRxxtPrefix(key)
+}
+
 var uniqueWorkerLock sync.RWMutex
 var uniqueWorker *ExecWorker
 var noResetWorker = true
@@ -123,6 +188,12 @@ func internalGetWorker() (w *ExecWorker) {
 }
 
 func internalResetWorkerNoLock() (w *ExecWorker) {
+	w = newWorker()
+	uniqueWorker = w
+	return
+}
+
+func newWorker() (w *ExecWorker) {
 	w = &ExecWorker{
 		envPrefixes:  []string{"CMDR"},
 		rxxtPrefixes: []string{"app"},
@@ -153,8 +224,7 @@ func internalResetWorkerNoLock() (w *ExecWorker) {
 
 		doNotLoadingConfigFiles: false,
 
-		currentHelpPainter: new(helpPainter),
-
+		defaultStdin:  os.Stdin,
 		defaultStdout: bufio.NewWriterSize(os.Stdout, 16384),
 		defaultStderr: bufio.NewWriterSize(os.Stderr, 16384),
 
@@ -165,16 +235,16 @@ func internalResetWorkerNoLock() (w *ExecWorker) {
 
 		helpTailLine: defaultTailLine,
 	}
+	w.currentHelpPainter = &helpPainter{w: w}
+	w.rxxtOptions.w = w
 	WithEnvVarMap(nil)(w)
-
-	uniqueWorker = w
 	return
 }
 
 // InternalExecFor is an internal helper, esp for debugging
 func (w *ExecWorker) InternalExecFor(rootCmd *RootCommand, args []string) (last *Command, err error) {
 	var (
-		pkg          = new(ptpkg)
+		pkg          = &ptpkg{w: w}
 		goCommand    = &rootCmd.Command
 		stopF, stopC bool
 		matched      bool
@@ -183,17 +253,35 @@ func (w *ExecWorker) InternalExecFor(rootCmd *RootCommand, args []string) (last
 	if w.rootCommand == nil {
 		w.setupRootCommand(rootCmd)
 	}
+	rootCmd.w = w
 
 	// initExitingChannelForFsWatcher()
 	defer w.postExecFor(rootCmd)
 
-	err = w.preprocess(rootCmd, args)
+	if args, err = w.expandResponseFiles(args); err != nil {
+		w.printError(err)
+		return
+	}
+
+	if !w.inShell {
+		// the xref and the options are built once for the shell
+		err = w.preprocess(rootCmd, args)
+	}
 
 	if err == nil {
+		if cx, ok, e := w.completeArgs(rootCmd, args); ok {
+			last, err = cx, e
+			return
+		}
+
 		for pkg.i = 1; pkg.i < len(args); pkg.i++ {
 			// if pkg.ResetAnd(args[pkg.i]) == 0 {
 			// 	continue
 			// }
+			if args, err = w.expandAlias(pkg, goCommand, args); err != nil {
+				w.printError(err)
+				return
+			}
 			lr := pkg.ResetAnd(args[pkg.i])
 			flog("--> parsing %q (idx=%v, len=%v) | pkg.lastCommandHeld=%v", pkg.a, pkg.i, lr, pkg.lastCommandHeld)
 
@@ -214,7 +302,7 @@ func (w *ExecWorker) InternalExecFor(rootCmd *RootCommand, args []string) (last
 			if err != nil {
 				var e *ErrorForCmdr
 				if errors.As(err, &e) {
-					ferr("%v", e)
+					w.printError(e)
 					if !e.Ignorable {
 						return
 					}
@@ -241,8 +329,29 @@ func (w *ExecWorker) InternalExecFor(rootCmd *RootCommand, args []string) (last
 	return
 }
 
+// printError prints err, which won't be printed again by runShell.
+func (w *ExecWorker) printError(err error) {
+	w.ferr("%v", err)
+	var e *ErrorForCmdr
+	if errors.As(err, &e) {
+		e.printed = true
+	}
+}
+
 func (w *ExecWorker) xxTestCmd(pkg *ptpkg, goCommand **Command, rootCmd *RootCommand, args []string) (matched, stopC, stopF bool, err error) {
-	if len(pkg.a) > 0 && (pkg.a[0] == '-' || pkg.a[0] == '/' || pkg.a[0] == '~') {
+	if pkg.optionsEnded {
+		// after '--', or the first operand in POSIX mode
+		stopC = true
+		pkg.remainArgs = append(pkg.remainArgs, pkg.a)
+		return
+	}
+	if pkg.a == "--" && w.parsingMode.conforming() {
+		// '--' terminates the options, the following args are operands
+		pkg.optionsEnded = true
+		return
+	}
+
+	if w.isSwitchChar(pkg.a) {
 		if len(pkg.a) == 1 {
 			// pkg.needHelp = true
 			// pkg.needFlagsHelp = true
@@ -293,6 +402,7 @@ func (w *ExecWorker) xxTestCmd(pkg *ptpkg, goCommand **Command, rootCmd *RootCom
 			// if pkg.i == len(args) {	pkg.i-- }
 			stopC = true
 			pkg.remainArgs = append(pkg.remainArgs, pkg.a)
+			pkg.optionsEnded = w.parsingMode == ParsingModePOSIX
 			return
 		}
 
@@ -300,18 +410,28 @@ func (w *ExecWorker) xxTestCmd(pkg *ptpkg, goCommand **Command, rootCmd *RootCom
 		// if matched, stop, err = cmdMatching(pkg, goCommand, args); stop || err != nil {
 		// 	return
 		// }
-		matched, stopC, err = w.cmdMatching(pkg, goCommand, args)
+		matched, stopC, stopF, err = w.cmdMatching(pkg, goCommand, args)
 	}
 	return
 }
 
+// isSwitchChar reports whether a looks like a flag. The switch chars are
+// '-', '/' and '~' in ParsingModeCompat, but only '-' in the others, and
+// a single '-' is an operand there, such as the stdin.
+func (w *ExecWorker) isSwitchChar(a string) bool {
+	if w.parsingMode.conforming() {
+		return len(a) > 1 && a[0] == '-'
+	}
+	return len(a) > 0 && (a[0] == '-' || a[0] == '/' || a[0] == '~')
+}
+
 func (w *ExecWorker) preprocess(rootCmd *RootCommand, args []string) (err error) {
 	flog("--> preprocess")
 	for _, x := range w.beforeXrefBuilding {
 		x(rootCmd, args)
 	}
 
-	err = w.buildXref(rootCmd)
+	err = w.buildXref(rootCmd, args)
 
 	if err == nil {
 		flog("--> preprocess / rxxtOptions.buildAutomaticEnv()")
@@ -407,6 +527,10 @@ func (w *ExecWorker) doInvokeCommand(pkg *ptpkg, rootCmd *RootCommand, goCommand
 			}()
 		}
 
+		if remainArgs, err = w.promptMissing(goCommand, remainArgs); err != nil {
+			return
+		}
+
 		if err = w.checkArgs(pkg, rootCmd, goCommand, remainArgs); err != nil {
 			return
 		}
@@ -460,6 +584,18 @@ func (w *ExecWorker) checkArgs(pkg *ptpkg, rootCmd *RootCommand, goCommand *Comm
 		return
 	}
 
+	if err = w.checkFlagConstraints(goCommand); err != nil {
+		return
+	}
+
+	if err = w.checkRangedFlags(goCommand); err != nil {
+		return
+	}
+
+	if err = w.parsePositionalArgs(goCommand, remainArgs); err != nil {
+		return
+	}
+
 	if w.afterArgsParsed != nil {
 		if err = w.afterArgsParsed(goCommand, remainArgs); err == ErrShouldBeStopException {
 			return
@@ -469,44 +605,148 @@ func (w *ExecWorker) checkArgs(pkg *ptpkg, rootCmd *RootCommand, goCommand *Comm
 	return
 }
 
+// checkRequiredArgs checks the required flags of goCommand and its
+// parents, and the grouped requirements of goCommand only. All missed
+// ones are reported in one error.
 func (w *ExecWorker) checkRequiredArgs(goCommand *Command, remainArgs []string) (err error) {
-	c := errors.NewContainer("required flag missed")
-
-	cmd := goCommand
-UP:
-	for gn, gv := range cmd.allFlags {
-		for fn, fv := range gv {
-			if fv.Required && fv.times < 1 {
-				c.Attach(errors.New("\n    The required flag %q in group %q missed", fn, gn))
+	var missed []string
+	for cmd := goCommand; cmd != nil; cmd = cmd.owner {
+		for _, flg := range cmd.Flags {
+			if flg.Required && !w.flagGiven(flg) {
+				missed = append(missed, flg.GetTitleZshFlagName())
 			}
 		}
 	}
-	if cmd.HasParent() {
-		cmd = cmd.owner
-		goto UP
+
+	for _, group := range goCommand.AtLeastOneOf {
+		var names []string
+		given := false
+		for _, fn := range group {
+			if flg := findFlagInChain(goCommand, fn); flg != nil {
+				names = append(names, flg.GetTitleZshFlagName())
+				given = given || w.flagGiven(flg)
+			}
+		}
+		if !given && len(names) > 0 {
+			missed = append(missed, fmt.Sprintf("one of (%v)", strings.Join(names, " ")))
+		}
+	}
+
+	for _, group := range goCommand.RequiredTogether {
+		var givenNames, others []*Flag
+		for _, fn := range group {
+			if flg := findFlagInChain(goCommand, fn); flg != nil {
+				if w.flagGiven(flg) {
+					givenNames = append(givenNames, flg)
+				} else {
+					others = append(others, flg)
+				}
+			}
+		}
+		if len(givenNames) > 0 {
+			for _, flg := range others {
+				missed = append(missed, fmt.Sprintf("%v (together with %v)", flg.GetTitleZshFlagName(), givenNames[0].GetTitleZshFlagName()))
+			}
+		}
 	}
 
-	if !c.IsEmpty() {
-		err = c.Error()
+	if len(missed) > 0 {
+		err = newError(false, errRequiredFlagsMissed, strings.Join(missed, ", "), goCommand.GetName())
 	}
 	return
 }
 
+// flagGiven reports whether flg was given a value, from command-line,
+// environment variables or config files.
+func (w *ExecWorker) flagGiven(flg *Flag) bool {
+	return flg.times > 0 || w.valueSource(flg, w.wrapWithRxxtPrefix(w.backtraceFlagNames(flg))) != ""
+}
+
+// findFlagInChain finds the flag by its long title in cmd and its parents.
+func findFlagInChain(cmd *Command, full string) *Flag {
+	for c := cmd; c != nil; c = c.owner {
+		for _, flg := range c.Flags {
+			if flg.Full == full {
+				return flg
+			}
+		}
+	}
+	return nil
+}
+
+// checkRangedFlags validates the values of the ranged flags of
+// goCommand and its parents, which were not specified in command-line
+// but might come from the environment variables or the config files.
+func (w *ExecWorker) checkRangedFlags(goCommand *Command) (err error) {
+	for cmd := goCommand; cmd != nil; cmd = cmd.owner {
+		for _, flg := range cmd.Flags {
+			if !flg.hasRange() || flg.times > 0 {
+				continue
+			}
+
+			key := w.wrapWithRxxtPrefix(w.backtraceFlagNames(flg))
+			var v interface{}
+			switch reflect.ValueOf(flg.DefaultValue).Kind() {
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				v = w.rxxtOptions.GetUint64Ex(key)
+			case reflect.Float32, reflect.Float64:
+				v = w.rxxtOptions.GetFloat64Ex(key)
+			default:
+				if _, ok := flg.DefaultValue.(time.Duration); ok {
+					v = w.rxxtOptions.GetDuration(key)
+				} else {
+					v = w.rxxtOptions.GetInt64Ex(key)
+				}
+			}
+
+			// an out-of-range default value is the developer's choice
+			if !flg.inRange(v) && fmt.Sprint(v) != fmt.Sprint(flg.DefaultValue) {
+				src := w.valueSource(flg, key)
+				if src == "" {
+					src = "options"
+				}
+				err = newError(false, errValueOutOfRange,
+					v, flg.GetTitleZshFlagName(), flg.rangeString(), src, cmd.GetName(),
+				)
+				return
+			}
+		}
+	}
+	return
+}
+
+// valueSource returns where the value of flg came from, an environment
+// variable or the config files. It returns "" if neither.
+func (w *ExecWorker) valueSource(flg *Flag, key string) string {
+	for _, ek := range flg.EnvVars {
+		if _, ok := os.LookupEnv(ek); ok {
+			return "env var " + ek
+		}
+	}
+	if ek := w.rxxtOptions.envKey(key); os.Getenv(ek) != "" {
+		return "env var " + ek
+	}
+	if w.rxxtOptions.fromConfigFile(key) {
+		return "config"
+	}
+	return ""
+}
+
 func (w *ExecWorker) checkStates(pkg *ptpkg) {
 	if !pkg.needHelp {
-		pkg.needHelp = GetBoolP(w.getPrefix(), "help")
+		pkg.needHelp = w.rxxtOptions.GetBoolEx(w.wrapWithRxxtPrefix("help"))
 	}
 
 	if w.noColor {
-		Set("no-color", true)
+		w.rxxtOptions.Set("no-color", true)
 	}
 
 	if w.noEnvOverrides {
-		Set("no-env-overrides", true)
+		w.rxxtOptions.Set("no-env-overrides", true)
 	}
 
 	if w.strictMode {
-		Set("strict-mode", true)
+		w.rxxtOptions.Set("strict-mode", true)
 	}
 }
 
@@ -536,7 +776,7 @@ func (w *ExecWorker) checkStates(pkg *ptpkg) {
 // }
 
 func (w *ExecWorker) invokeCommand(rootCmd *RootCommand, goCommand *Command, remainArgs []string) (err error) {
-	if unhandledErrorHandler != nil {
+	if w.unhandledErrorHandler != nil {
 		defer func() {
 			// fmt.Println("defer caller")
 			if ex := recover(); ex != nil {
@@ -549,7 +789,7 @@ func (w *ExecWorker) invokeCommand(rootCmd *RootCommand, goCommand *Command, rem
 				// fmt.Println("stacktrace from panic: \n" + string(debug.Stack()))
 
 				// fmt.Printf("recover success. error: %v", ex)
-				unhandledErrorHandler(ex)
+				w.unhandledErrorHandler(ex)
 				if e, ok := ex.(error); ok {
 					err = e
 				}
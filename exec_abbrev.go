@@ -0,0 +1,82 @@
+// Copyright © 2020 Hedzr Yeh.
+
+package cmdr
+
+import (
+	"sort"
+	"strings"
+)
+
+// abbrevCmd returns the name of the visible sub-command of cmd which
+// starts with the prefix a, such as "sta" for "start", see also
+// WithAbbreviations. It returns an empty string if nothing matched, and
+// an error if the prefix is ambiguous.
+func abbrevCmd(cmd *Command, a string) (key string, err error) {
+	candidates := make(map[*Command]string)
+	for k, cc := range cmd.plainCmds {
+		if cc.Hidden || !strings.HasPrefix(k, a) {
+			continue
+		}
+		if old, ok := candidates[cc]; !ok || k == cc.Full || (old != cc.Full && k < old) {
+			candidates[cc] = k
+		}
+	}
+
+	var keys []string
+	for _, k := range candidates {
+		keys = append(keys, k)
+	}
+	switch len(keys) {
+	case 0:
+	case 1:
+		key = keys[0]
+	default:
+		sort.Strings(keys)
+		err = newError(false, errAmbiguousCmd, a, strings.Join(keys, ", "), cmd.GetName())
+	}
+	return
+}
+
+// abbrevLongFlag returns the long name of the visible flag which starts
+// with the prefix fn, such as "verb" for "verbose", see also
+// WithAbbreviations. The flags of cmd and of its parents are matched, a
+// parent flag is shadowed by the flag in the same name of cmd. It
+// returns fn itself if nothing matched or a flag is named as fn exactly,
+// and an error if the prefix is ambiguous.
+func abbrevLongFlag(cmd *Command, fn string) (key string, err error) {
+	type target struct {
+		flg     *Flag
+		negated bool // --no-xxx for a Negatable flag
+	}
+	candidates, seen := make(map[target]string), make(map[string]bool)
+	for c := cmd; c != nil; c = c.owner {
+		for k, flg := range c.plainLongFlags {
+			if k == fn {
+				return fn, nil
+			}
+			if seen[k] || flg.Hidden || !strings.HasPrefix(k, fn) {
+				continue
+			}
+			seen[k] = true
+			t := target{flg, flg.isNegated(k)}
+			if old, ok := candidates[t]; !ok || k == flg.Full || (old != flg.Full && k < old) {
+				candidates[t] = k
+			}
+		}
+	}
+
+	var keys []string
+	for _, k := range candidates {
+		keys = append(keys, k)
+	}
+	switch len(keys) {
+	case 0:
+		key = fn
+	case 1:
+		key = keys[0]
+	default:
+		sort.Strings(keys)
+		err = newError(false, errAmbiguousFlag, "--"+fn, "--"+strings.Join(keys, ", --"), cmd.GetName())
+	}
+	return
+}
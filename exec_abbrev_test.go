@@ -0,0 +1,63 @@
+// Copyright © 2020 Hedzr Yeh.
+
+package cmdr_test
+
+import (
+	"fmt"
+	"github.com/hedzr/cmdr"
+	"testing"
+)
+
+func TestAbbreviations(t *testing.T) {
+	c := &cmdrTester{T: t}
+	c.newRoot = func() *cmdr.RootCommand {
+		root := cmdr.Root("abbr", "1.0.0")
+		cmdr.NewString().Titles("name", "n").AttachTo(root)
+		cmdr.NewString().Titles("names-file", "nf").AttachTo(root)
+		server := root.NewSubCommand("server", "s")
+		cmdr.NewSubCmd().Titles("service", "svc").AttachTo(root)
+		for _, name := range []string{"start", "status", "stop"} {
+			cmd := server.NewSubCommand(name).Action(c.action)
+			cmdr.NewInt().Titles("port", "p").AttachTo(cmd)
+			cmdr.NewString().Titles("portal", "").AttachTo(cmd)
+			cmdr.NewBool(true).Titles("cache", "").Negatable(true).AttachTo(cmd)
+		}
+		return root.RootCommand()
+	}
+	c.result = func(w *cmdr.ExecWorker) string {
+		if c.cmd == nil {
+			return none
+		}
+		hit := c.cmd.GetTitleName()
+		opts, key := optionsOf(w, "")
+		sub := func(k string) string { return key("server." + hit + "." + k) }
+		return fmt.Sprintf("%v n=%q nf=%q p=%v portal=%q cache=%v", hit,
+			opts.GetString(key("name")), opts.GetString(key("names-file")),
+			opts.GetIntEx(sub("port")), opts.GetString(sub("portal")), opts.GetBoolEx(sub("cache")))
+	}
+
+	c.opts = []cmdr.ExecOption{cmdr.WithAbbreviations(true)}
+	c.expect(conformance{
+		{"server start", `start n="" nf="" p=0 portal="" cache=true`},
+		{"serve star", `start n="" nf="" p=0 portal="" cache=true`},
+		{"s sto", `stop n="" nf="" p=0 portal="" cache=true`},
+		{"ser start", "ambiguous command 'ser', could be: server, service, under command 'abbr'"},
+		{"server st", "ambiguous command 'st', could be: start, status, stop, under command 'server'"},
+		{"server stat --port 8 --porta x", `status n="" nf="" p=8 portal="x" cache=true`},
+		{"server stat --po 8", "ambiguous option '--po', could be: --port, --portal, under command 'status'"},
+		{"server stat --name x --no-ca", `status n="x" nf="" p=0 portal="" cache=false`},
+		{"server stat --nam x", "ambiguous option '--nam', could be: --name, --names-file, under command 'status'"},
+		{"server stat --nameconsul --names y", `status n="consul" nf="y" p=0 portal="" cache=true`},
+	})
+	c.opts = append(c.opts, cmdr.WithParsingMode(cmdr.ParsingModeGNU))
+	c.expect(conformance{
+		{"serve star --name=x --porta=y", `start n="x" nf="" p=0 portal="y" cache=true`},
+		{"serve star --nameconsul", none},
+	})
+
+	// disabled by default
+	c.opts = nil
+	c.expect(conformance{
+		{"serve star", none},
+	})
+}
@@ -6,10 +6,16 @@ package cmdr
 
 import "github.com/hedzr/cmdr/tool"
 
-func (w *ExecWorker) cmdMatching(pkg *ptpkg, goCommand **Command, args []string) (matched, stop bool, err error) {
+func (w *ExecWorker) cmdMatching(pkg *ptpkg, goCommand **Command, args []string) (matched, stop, stopF bool, err error) {
 	// command, files
-	if cmd, ok := (*goCommand).plainCmds[pkg.a]; ok {
-		cmd.strHit = pkg.a
+	key := pkg.a
+	if _, ok := (*goCommand).plainCmds[key]; !ok && w.abbreviations {
+		if key, err = abbrevCmd(*goCommand, pkg.a); err != nil {
+			return
+		}
+	}
+	if cmd, ok := (*goCommand).plainCmds[key]; ok {
+		cmd.strHit = key
 		*goCommand = cmd
 		matched = true
 		flog("    -> command %q hit (a=%q, idx=%v)...", cmd.GetTitleName(), pkg.a, pkg.i)
@@ -25,6 +31,12 @@ func (w *ExecWorker) cmdMatching(pkg *ptpkg, goCommand **Command, args []string)
 		return
 	}
 
+	if path, ok := w.pluginMatching(pkg, *goCommand); ok {
+		// a git-style plugin, such as `app-foo` for `app foo`
+		stopF, err = true, w.runPlugin(path, args[pkg.i+1:])
+		return
+	}
+
 	flog("    . adding unknown command %q", pkg.a)
 	pkg.unknownCmds = append(pkg.unknownCmds, pkg.a)
 	unknownCommand(pkg, *goCommand, args)
@@ -66,6 +78,13 @@ func (w *ExecWorker) flagsPrepare(pkg *ptpkg, goCommand **Command, args []string
 		// long flag
 		pkg.fn = pkg.a[2:]
 		pkg.findValueAttached(&pkg.fn)
+		if w.abbreviations {
+			// the unique prefix is taken before the value fused into the
+			// name, such as --nameconsul, see matchForLongFlags
+			if pkg.fn, err = abbrevLongFlag(*goCommand, pkg.fn); err != nil {
+				return
+			}
+		}
 
 	} else {
 
@@ -82,7 +101,7 @@ func (w *ExecWorker) flagsPrepare(pkg *ptpkg, goCommand **Command, args []string
 		}
 
 		pkg.suffix = pkg.a[len(pkg.a)-1]
-		if pkg.suffix == '+' || pkg.suffix == '-' {
+		if (pkg.suffix == '+' || pkg.suffix == '-') && !w.parsingMode.conforming() {
 			pkg.a = pkg.a[0 : len(pkg.a)-1]
 		} else {
 			pkg.suffix = 0
@@ -104,7 +123,7 @@ func (w *ExecWorker) flagsPrepare(pkg *ptpkg, goCommand **Command, args []string
 func (w *ExecWorker) flagsMatching(pkg *ptpkg, cc *Command, goCommand **Command, args []string) (matched, stop bool, err error) {
 	var upLevel bool
 GO_UP:
-	pkg.found = false
+	pkg.found, matched = false, false
 	if pkg.short {
 		a := "-" + pkg.fn + pkg.savedFn
 		flog("    .  . matching short flag for %q", a)
@@ -131,7 +150,7 @@ GO_UP:
 			cc = cc.owner
 			goto GO_UP
 		}
-		if !pkg.assigned && pkg.short {
+		if !pkg.assigned && pkg.short && !w.parsingMode.conforming() {
 			// try matching 2-chars short opt
 			if len(pkg.savedFn) > 0 {
 				fnf := pkg.fn + pkg.savedFn
@@ -169,7 +188,7 @@ func (w *ExecWorker) flagsMatched(pkg *ptpkg, goCommand *Command, args []string)
 				return
 			}
 		}
-		if isBool(pkg.flg.DefaultValue) || isNil1(pkg.flg.DefaultValue) {
+		if (isBool(pkg.flg.DefaultValue) || isNil1(pkg.flg.DefaultValue)) && !pkg.negated() {
 			flog("    .  . [tryToggleGroup] %q = %v", pkg.fn, pkg.val)
 			pkg.tryToggleGroup()
 		}
@@ -189,6 +208,11 @@ func (w *ExecWorker) flagsMatched(pkg *ptpkg, goCommand *Command, args []string)
 }
 
 func (w *ExecWorker) matchForLongFlags(fn string, cc *Command, pkg *ptpkg) (ok bool) {
+	if w.parsingMode.conforming() {
+		// no value fused into the name, such as --nameconsul
+		pkg.flg, ok = cc.plainLongFlags[fn]
+		return
+	}
 	var ln = len(fn)
 	for ; ln > 1; ln-- {
 		fn = pkg.fn[0:ln]
@@ -0,0 +1,84 @@
+// Copyright © 2020 Hedzr Yeh.
+
+package cmdr_test
+
+import (
+	"fmt"
+	"github.com/hedzr/cmdr"
+	"testing"
+)
+
+func TestParsingModes(t *testing.T) {
+	c := &cmdrTester{T: t}
+	c.newRoot = func() *cmdr.RootCommand {
+		root := cmdr.Root("parsed", "1.0.0")
+		cmd := root.NewSubCommand("run").Action(c.action)
+		cmdr.NewBool().Titles("all", "a").AttachTo(cmd)
+		cmdr.NewBool().Titles("long", "l").AttachTo(cmd)
+		cmdr.NewString().Titles("name", "n").AttachTo(cmd)
+		cmdr.NewBool().Titles("no-verify", "nv").AttachTo(cmd)
+		cmdr.NewInt().Titles("offset", "o").AttachTo(cmd)
+		cmdr.NewBool(true).Titles("force", "f").AttachTo(cmd)
+		return root.RootCommand()
+	}
+	c.result = func(w *cmdr.ExecWorker) string {
+		if c.cmd == nil {
+			return none
+		}
+		opts, key := optionsOf(w, "run")
+		return fmt.Sprintf("a=%v l=%v n=%q nv=%v o=%v f=%v %q",
+			opts.GetBoolEx(key("all")), opts.GetBoolEx(key("long")), opts.GetString(key("name")),
+			opts.GetBoolEx(key("no-verify")), opts.GetIntEx(key("offset")), opts.GetBoolEx(key("force")), c.args)
+	}
+
+	for _, tc := range []struct {
+		name  string
+		mode  cmdr.ParsingMode
+		table conformance
+	}{
+		{"compat", cmdr.ParsingModeCompat, conformance{
+			{"run -al x", `a=true l=true n="" nv=false o=0 f=true ["x"]`},
+			{"run -nconsul", `a=false l=false n="consul" nv=false o=0 f=true []`},
+			{"run -n consul", `a=false l=false n="consul" nv=false o=0 f=true []`},
+			{"run -n=consul", `a=false l=false n="consul" nv=false o=0 f=true []`},
+			{"run -nv", `a=false l=false n="" nv=true o=0 f=true []`},
+			{"run --nameconsul", `a=false l=false n="consul" nv=false o=0 f=true []`},
+			{"run x -a", `a=true l=false n="" nv=false o=0 f=true ["x"]`},
+			{"run -f-", `a=false l=false n="" nv=false o=0 f=false []`},
+			{"run /a", `a=true l=false n="" nv=false o=0 f=true []`},
+		}},
+		{"gnu", cmdr.ParsingModeGNU, conformance{
+			{"run -al x", `a=true l=true n="" nv=false o=0 f=true ["x"]`},
+			{"run -nconsul", `a=false l=false n="consul" nv=false o=0 f=true []`},
+			{"run -n consul", `a=false l=false n="consul" nv=false o=0 f=true []`},
+			{"run -n=consul", `a=false l=false n="consul" nv=false o=0 f=true []`},
+			{"run -nv", `a=false l=false n="v" nv=false o=0 f=true []`},
+			{"run -an v", `a=true l=false n="v" nv=false o=0 f=true []`},
+			{"run --no-verify", `a=false l=false n="" nv=true o=0 f=true []`},
+			{"run --nameconsul", none},
+			{"run x -a", `a=true l=false n="" nv=false o=0 f=true ["x"]`},
+			{"run -f-", none},
+			{"run /a ~a -", `a=false l=false n="" nv=false o=0 f=true ["/a" "~a" "-"]`},
+			{"run -o -5", `a=false l=false n="" nv=false o=-5 f=true []`},
+			{"run --name -a x", `a=false l=false n="-a" nv=false o=0 f=true ["x"]`},
+			{"run -a -- -l x", `a=true l=false n="" nv=false o=0 f=true ["-l" "x"]`},
+			{"run -n", "missing value for option '--name', under command 'run'"},
+		}},
+		{"posix", cmdr.ParsingModePOSIX, conformance{
+			{"run -al x", `a=true l=true n="" nv=false o=0 f=true ["x"]`},
+			{"run -nconsul -l", `a=false l=true n="consul" nv=false o=0 f=true []`},
+			{"run -nv", `a=false l=false n="v" nv=false o=0 f=true []`},
+			{"run x -a", `a=false l=false n="" nv=false o=0 f=true ["x" "-a"]`},
+			{"run -a x -- -l", `a=true l=false n="" nv=false o=0 f=true ["x" "--" "-l"]`},
+			{"run -o -5 x", `a=false l=false n="" nv=false o=-5 f=true ["x"]`},
+			{"run -- -a", `a=false l=false n="" nv=false o=0 f=true ["-a"]`},
+			{"run /a", `a=false l=false n="" nv=false o=0 f=true ["/a"]`},
+			{"run --offset", "missing value for option '--offset', under command 'run'"},
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c.T, c.opts = t, []cmdr.ExecOption{cmdr.WithParsingMode(tc.mode)}
+			c.expect(tc.table)
+		})
+	}
+}
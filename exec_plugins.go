@@ -0,0 +1,149 @@
+// Copyright © 2020 Hedzr Yeh.
+
+package cmdr
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// pluginMatching returns the path of the plugin for the unknown command
+// pkg.a of root, see also WithPlugins. It's called only if neither a
+// command nor an abbreviation matched, so the builtin commands are never
+// overridden by a plugin.
+func (w *ExecWorker) pluginMatching(pkg *ptpkg, cmd *Command) (path string, ok bool) {
+	if !w.plugins || cmd.owner != nil || strings.ContainsAny(pkg.a, `/\`) {
+		return
+	}
+	return w.lookupPlugin(pkg.a)
+}
+
+// lookupPlugin searches the executable `<AppName>-<name>` in the plugin
+// dirs, and then on PATH.
+func (w *ExecWorker) lookupPlugin(name string) (path string, ok bool) {
+	file := w.rootCommand.AppName + "-" + name
+	for _, dir := range w.pluginDirs {
+		if p, err := exec.LookPath(filepath.Join(dir, file)); err == nil {
+			return p, true
+		}
+	}
+	if p, err := exec.LookPath(file); err == nil {
+		return p, true
+	}
+	return
+}
+
+// findPlugins returns the paths of all plugins in the plugin dirs and
+// on PATH, keyed by the command names. The first one wins if a name is
+// found in several dirs.
+func (w *ExecWorker) findPlugins() (plugins map[string]string) {
+	plugins = make(map[string]string)
+	prefix := w.rootCommand.AppName + "-"
+	for _, dir := range append(w.pluginDirs, filepath.SplitList(os.Getenv("PATH"))...) {
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, fi := range files {
+			name := fi.Name()
+			if !strings.HasPrefix(name, prefix) || fi.IsDir() {
+				continue
+			}
+			if runtime.GOOS == "windows" {
+				name = strings.TrimSuffix(name, filepath.Ext(name))
+			} else if fi.Mode()&0111 == 0 {
+				continue
+			}
+			if name = name[len(prefix):]; name == "" {
+				continue
+			}
+			if _, ok := plugins[name]; !ok {
+				plugins[name] = filepath.Join(dir, fi.Name())
+			}
+		}
+	}
+	return
+}
+
+// attachPluginCommands lists the plugins in the Plugins group of the
+// help screen of root. They are not the sub-commands of root, so that
+// the command-line parsing is not affected.
+func (w *ExecWorker) attachPluginCommands(root *RootCommand) {
+	if !w.plugins {
+		return
+	}
+	if _, ok := root.allCmds[PluginsGroup]; ok {
+		return
+	}
+	group := make(map[string]*Command)
+	for name, path := range w.findPlugins() {
+		if _, builtin := root.plainCmds[name]; builtin {
+			continue
+		}
+		group[name] = &Command{
+			BaseOpt: BaseOpt{
+				Full:        name,
+				Description: fmt.Sprintf("run the plugin %v", path),
+				Group:       PluginsGroup,
+				owner:       &root.Command,
+			},
+			TailPlaceHolder: "[args...]",
+		}
+	}
+	if len(group) > 0 {
+		root.allCmds[PluginsGroup] = group
+	}
+}
+
+// runPlugin runs the plugin with args, the input and output streams of
+// the worker and the environment are inherited, and the options are
+// exported as the env vars too, such as `CMDR_APP_SERVER_PORT=8080`.
+func (w *ExecWorker) runPlugin(path string, args []string) (err error) {
+	ow, oerr := w.rootCommand.ow, w.rootCommand.oerr
+	_ = ow.Flush()
+	_ = oerr.Flush()
+
+	c := exec.Command(path, args...)
+	c.Stdin, c.Stdout, c.Stderr = w.defaultStdin, ow, oerr
+	if w.stdinReader != nil {
+		// the lines buffered by the shell or the prompts come first
+		c.Stdin = w.stdinReader
+	}
+	c.Env = append(os.Environ(), w.rxxtOptions.exportEnv()...)
+	flog("    -> running plugin %q with %q", path, args)
+	err = c.Run()
+	_ = ow.Flush()
+	_ = oerr.Flush()
+	return
+}
+
+// exportEnv returns the options as the env vars, in the names which
+// buildAutomaticEnv reads back. The maps are skipped.
+func (s *Options) exportEnv() (env []string) {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+	for key, val := range s.entries {
+		var text string
+		switch x := val.(type) {
+		case nil, map[string]interface{}:
+			continue
+		case []string:
+			text = strings.Join(x, ",")
+		case []interface{}:
+			a := make([]string, len(x))
+			for i, v := range x {
+				a[i] = fmt.Sprint(v)
+			}
+			text = strings.Join(a, ",")
+		default:
+			text = fmt.Sprint(x)
+		}
+		env = append(env, s.envKey(key)+"="+text)
+	}
+	return
+}
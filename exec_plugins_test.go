@@ -0,0 +1,93 @@
+// Copyright © 2020 Hedzr Yeh.
+
+package cmdr_test
+
+import (
+	"github.com/hedzr/cmdr"
+	"gopkg.in/hedzr/errors.v2"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the plugins are shell scripts")
+	}
+	dir, err := ioutil.TempDir("", "cmdr-plugins")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	out := path.Join(dir, "out.txt")
+	for name, content := range map[string]string{
+		"plg-hello":   "#!/bin/sh\necho \"$*|$CMDR_APP_NAME|$CMDR_APP_TAGS\" > " + out + "\n",
+		"plg-fail":    "#!/bin/sh\nexit 3\n",
+		"plg-version": "#!/bin/sh\necho version > " + out + "\n",
+		"plg-noexec":  "#!/bin/sh\n",
+		"plg-echo":    "#!/bin/sh\nread line\necho \"out:$line\"\necho err >&2\n",
+		"plg-serv":    "#!/bin/sh\necho serv > " + out + "\n",
+	} {
+		mode := os.FileMode(0755)
+		if name == "plg-noexec" {
+			mode = 0644
+		}
+		if err = ioutil.WriteFile(path.Join(dir, name), []byte(content), mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := &cmdrTester{T: t}
+	c.newRoot = func() *cmdr.RootCommand {
+		root := cmdr.Root("plg", "1.0.0")
+		cmdr.NewString().Titles("name", "n").AttachTo(root)
+		cmdr.NewStringSlice().Titles("tags", "t").AttachTo(root)
+		root.NewSubCommand("server", "s").Action(c.action)
+		return root.RootCommand()
+	}
+	c.result = func(w *cmdr.ExecWorker) string {
+		b, _ := ioutil.ReadFile(out)
+		_ = os.Remove(out)
+		if c.cmd != nil {
+			return c.cmd.GetTitleName()
+		}
+		return strings.TrimSpace(string(b))
+	}
+
+	c.opts = []cmdr.ExecOption{cmdr.WithPlugins(dir)}
+	c.expect(conformance{
+		{"--name x -t a,b hello a --b -c", "a --b -c|x|a,b"},
+		{"version", ""}, // the builtin command isn't overridden
+		{"server", "server"},
+		{"noexec", ""}, // the non-executable file is ignored
+	})
+	var exitErr *exec.ExitError
+	if _, _, err := c.run("fail"); !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+		t.Errorf("expect the exit code 3 of the plugin, but got %v", err)
+	}
+	c.expectOutput("--help", "Plugins", "hello", "fail", "run the plugin "+path.Join(dir, "plg-hello"))
+	c.expectNoOutput("--help", "noexec", "run the plugin "+path.Join(dir, "plg-version"))
+
+	// neither a command nor its abbreviation is shadowed by a plugin
+	c.opts = []cmdr.ExecOption{cmdr.WithPlugins(dir), cmdr.WithAbbreviations(true)}
+	c.expect(conformance{
+		{"serv", "server"},
+	})
+
+	// disabled by default
+	c.opts = nil
+	c.expect(conformance{
+		{"hello a", ""},
+	})
+
+	// the streams of the worker are connected to the plugin
+	_, stdout, stderr, err := runWorker(c.newRoot(), []string{"plg", "echo"},
+		cmdr.WithPlugins(dir), cmdr.WithInternalInputStream(strings.NewReader("hi\n")))
+	if err != nil || stdout != "out:hi\n" || stderr != "err\n" {
+		t.Errorf("expect the plugin using the streams of the worker, but got %q, %q, %v", stdout, stderr, err)
+	}
+}
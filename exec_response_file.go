@@ -0,0 +1,134 @@
+// Copyright © 2020 Hedzr Yeh.
+
+package cmdr
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// maxResponseFileDepth is the limit of the nested response files.
+const maxResponseFileDepth = 10
+
+// expandResponseFiles replaces each argument `@path` with the arguments
+// read from the file path, see also WithResponseFiles. The program name
+// args[0], and the arguments after '--' are never expanded.
+func (w *ExecWorker) expandResponseFiles(args []string) (ret []string, err error) {
+	if !w.responseFiles || len(args) == 0 {
+		return args, nil
+	}
+	x := &responseFileExpander{out: []string{args[0]}}
+	err = x.expand(args[1:], nil, "", 0)
+	return x.out, err
+}
+
+type responseFileExpander struct {
+	out   []string
+	ended bool // '--' hit
+}
+
+// expand appends args to x.out, and expands the response files in them.
+// A quoted argument in a response file is never expanded. The relative
+// path of a nested response file is based on the dir of its parent.
+func (x *responseFileExpander) expand(args []string, quoted []bool, dir string, depth int) (err error) {
+	for i, a := range args {
+		if x.ended || len(a) < 2 || a[0] != '@' || (quoted != nil && quoted[i]) {
+			x.ended = x.ended || a == "--"
+			x.out = append(x.out, a)
+			continue
+		}
+
+		if depth >= maxResponseFileDepth {
+			return newError(false, errResponseFile, a, fmt.Errorf("nested too deeply, the limit is %v", maxResponseFileDepth))
+		}
+		path := a[1:]
+		if !filepath.IsAbs(path) && dir != "" {
+			path = filepath.Join(dir, path)
+		}
+		var b []byte
+		if b, err = ioutil.ReadFile(path); err != nil {
+			return newError(false, errResponseFile, a, err)
+		}
+		var words []string
+		var q []bool
+		if words, q, err = splitResponseFile(string(b)); err != nil {
+			return newError(false, errResponseFile, a, err)
+		}
+		if err = x.expand(words, q, filepath.Dir(path), depth+1); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// splitResponseFile splits the content of a response file into the
+// arguments, like a POSIX shell does:
+//
+//   - the arguments are separated by the whitespaces and the newlines;
+//   - a word starting with '#' comments out the rest of the line;
+//   - the text in single quotes is literal;
+//   - in double quotes, a backslash escapes '"', '\', '$', '`' only;
+//   - elsewhere a backslash escapes the next char;
+//   - a backslash-newline joins the lines.
+//
+// quoted reports whether each argument has a quoted part.
+func splitResponseFile(s string) (args []string, quoted []bool, err error) {
+	var sb strings.Builder
+	inWord, q := false, false
+	end := func() {
+		if inWord {
+			args, quoted = append(args, sb.String()), append(quoted, q)
+			sb.Reset()
+			inWord, q = false, false
+		}
+	}
+
+	rs := []rune(s)
+	for i := 0; i < len(rs); i++ {
+		switch c := rs[i]; {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			end()
+		case c == '#' && !inWord:
+			for i < len(rs) && rs[i] != '\n' {
+				i++
+			}
+		case c == '\\':
+			if i++; i < len(rs) && rs[i] != '\n' {
+				sb.WriteRune(rs[i])
+				inWord = true
+			}
+		case c == '\'':
+			j := i + 1
+			for j < len(rs) && rs[j] != '\'' {
+				j++
+			}
+			if j >= len(rs) {
+				return nil, nil, fmt.Errorf("unterminated single quote")
+			}
+			sb.WriteString(string(rs[i+1 : j]))
+			inWord, q, i = true, true, j
+		case c == '"':
+			j := i + 1
+			for ; j < len(rs) && rs[j] != '"'; j++ {
+				if rs[j] == '\\' && j+1 < len(rs) && strings.ContainsRune("\"\\$`\n", rs[j+1]) {
+					if j++; rs[j] != '\n' {
+						sb.WriteRune(rs[j])
+					}
+					continue
+				}
+				sb.WriteRune(rs[j])
+			}
+			if j >= len(rs) {
+				return nil, nil, fmt.Errorf("unterminated double quote")
+			}
+			inWord, q, i = true, true, j
+		default:
+			sb.WriteRune(c)
+			inWord = true
+		}
+	}
+	end()
+	return
+}
@@ -0,0 +1,69 @@
+// Copyright © 2020 Hedzr Yeh.
+
+package cmdr_test
+
+import (
+	"fmt"
+	"github.com/hedzr/cmdr"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestResponseFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cmdr-rsp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for name, content := range map[string]string{
+		"common.args":   "# the common options\n--name 'the app' -v\n@sub/more.args\n",
+		"sub/more.args": "--tag \"a b\" '@literal'\n",
+		"loop.args":     "-v @loop.args",
+		"bad.args":      "--name 'oops",
+	} {
+		_ = os.MkdirAll(path.Dir(path.Join(dir, name)), 0755)
+		if err = ioutil.WriteFile(path.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := &cmdrTester{T: t}
+	c.newRoot = func() *cmdr.RootCommand {
+		root := cmdr.Root("rsp", "1.0.0")
+		cmd := root.NewSubCommand("run").Action(c.action)
+		cmdr.NewString().Titles("name", "n").AttachTo(cmd)
+		cmdr.NewBool().Titles("verbose", "v").AttachTo(cmd)
+		cmdr.NewStringSlice().Titles("tag", "t").AttachTo(cmd)
+		return root.RootCommand()
+	}
+	c.result = func(w *cmdr.ExecWorker) string {
+		if c.cmd == nil {
+			return none
+		}
+		opts, key := optionsOf(w, "run")
+		return fmt.Sprintf("n=%q v=%v t=%q %q", opts.GetString(key("name")),
+			opts.GetBoolEx(key("verbose")), opts.GetStringSlice(key("tag")), c.args)
+	}
+
+	at := "@" + path.Join(dir, "")
+	c.opts = []cmdr.ExecOption{
+		cmdr.WithResponseFiles(true),
+		cmdr.WithParsingMode(cmdr.ParsingModeGNU), // passes the args after '--' to the action
+	}
+	c.expect(conformance{
+		{"run " + at + "/common.args x", `n="the app" v=true t=["a b"] ["@literal" "x"]`},
+		{"run --name @ x", `n="@" v=false t=[] ["x"]`},
+		{"run -- " + at + "/common.args", `n="" v=false t=[] ["` + at + `/common.args"]`},
+		{"run " + at + "/loop.args", "nested too deeply"},
+		{"run " + at + "/bad.args", "unterminated single quote"},
+		{"run " + at + "/missing.args", "cannot expand the response file '" + at + "/missing.args'"},
+	})
+
+	// disabled by default
+	c.opts = nil
+	c.expect(conformance{
+		{"run " + at + "/common.args", `n="" v=false t=[] ["` + at + `/common.args"]`},
+	})
+}
@@ -0,0 +1,171 @@
+// Copyright © 2020 Hedzr Yeh.
+
+package cmdr_test
+
+import (
+	"github.com/hedzr/cmdr"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewWorkerIsolated(t *testing.T) {
+	newRoot := func(appName string) *cmdr.RootCommand {
+		return &cmdr.RootCommand{
+			Command: cmdr.Command{
+				BaseOpt: cmdr.BaseOpt{
+					Name: appName,
+				},
+				Flags: []*cmdr.Flag{
+					{
+						BaseOpt:      cmdr.BaseOpt{Full: "name", Short: "n"},
+						DefaultValue: "",
+					},
+				},
+			},
+			AppName: appName,
+			Version: "1.0.0",
+		}
+	}
+
+	for _, tc := range []struct{ app, value string }{
+		{"app-one", "alice"},
+		{"app-two", "bob"},
+	} {
+		tc := tc
+		t.Run(tc.app, func(t *testing.T) {
+			t.Parallel()
+
+			w, _, _ := newTestWorker(newRoot(tc.app))
+			for i := 0; i < 20; i++ {
+				if err := w.Run([]string{tc.app, "--name", tc.value}); err != nil {
+					t.Fatal(err)
+				}
+				if v := w.GetOptions().GetString(w.WrapWithRxxtPrefix("name")); v != tc.value {
+					t.Fatalf("worker %q: expect %q but got %q", tc.app, tc.value, v)
+				}
+			}
+		})
+	}
+}
+
+func TestFlagRange(t *testing.T) {
+	newRoot := func() *cmdr.RootCommand {
+		return &cmdr.RootCommand{
+			Command: cmdr.Command{
+				BaseOpt: cmdr.BaseOpt{Name: "ranged"},
+				SubCommands: []*cmdr.Command{
+					{
+						BaseOpt: cmdr.BaseOpt{
+							Full:   "serve",
+							Action: func(cmd *cmdr.Command, args []string) (err error) { return },
+						},
+						Flags: []*cmdr.Flag{
+							{
+								BaseOpt:      cmdr.BaseOpt{Full: "port", Short: "p"},
+								DefaultValue: 8080,
+								EnvVars:      []string{"RANGED_PORT"},
+								Min:          1024,
+								Max:          65535,
+							},
+							{
+								BaseOpt:      cmdr.BaseOpt{Full: "ratio"},
+								DefaultValue: 0.5,
+								Min:          0,
+								Max:          1,
+							},
+							{
+								BaseOpt:      cmdr.BaseOpt{Full: "workers"},
+								DefaultValue: uint(4),
+								Min:          1,
+								Max:          64,
+							},
+							{
+								BaseOpt:      cmdr.BaseOpt{Full: "timeout"},
+								DefaultValue: time.Second,
+								Min:          int64(time.Millisecond),
+								Max:          int64(time.Minute),
+							},
+							{
+								BaseOpt:      cmdr.BaseOpt{Full: "lines", Short: "n"},
+								DefaultValue: 10,
+								HeadLike:     true,
+								Min:          1,
+								Max:          100,
+							},
+						},
+					},
+				},
+			},
+			AppName: "ranged",
+			Version: "1.0.0",
+		}
+	}
+
+	c := &cmdrTester{T: t, newRoot: newRoot}
+	c.expect(conformance{
+		{"serve --port 9000 --ratio 0.1 --workers 8 --timeout 3s -n 20", ""},
+		{"serve -20", ""},
+		{"serve --port 80", "value '80' for option '--port' is out of range [1024..65535] (from command-line)"},
+		{"serve --port=70000", "out of range [1024..65535]"},
+		{"serve --ratio 1.5", "value '1.5' for option '--ratio' is out of range [0..1]"},
+		{"serve --workers 0", "value '0' for option '--workers' is out of range [1..64]"},
+		{"serve --timeout 2m", "value '2m0s' for option '--timeout' is out of range [1ms..1m0s]"},
+		{"serve -n 200", "value '200' for option '--lines' is out of range [1..100]"},
+		{"serve -200", "value '200' for option '--lines' is out of range [1..100]"},
+	})
+	c.expectEnv("RANGED_PORT", "22", conformance{
+		{"serve", "value '22' for option '--port' is out of range [1024..65535] (from env var RANGED_PORT)"},
+		{"serve --port 2222", ""},
+	})
+	c.expectConfig("app:\n  serve:\n    workers: 128\n", conformance{
+		{"serve", "'--workers' is out of range [1..64] (from config)"},
+	})
+	c.expectOutput("serve --help", "in [1024..65535]", "in [0..1]", "in [1ms..1m0s]")
+}
+
+func TestRequiredFlags(t *testing.T) {
+	newRoot := func() *cmdr.RootCommand {
+		root := cmdr.Root("required", "1.0.0")
+		serve := root.NewSubCommand("serve").
+			Action(func(cmd *cmdr.Command, args []string) (err error) { return }).
+			RequiredTogether("cert", "key").
+			AtLeastOneOf("token", "password")
+		cmdr.NewString("").Titles("host", "H").EnvKeys("REQUIRED_HOST").Required().AttachTo(serve)
+		cmdr.NewInt(0).Titles("port", "p").Required().AttachTo(serve)
+		cmdr.NewString("").Titles("cert", "").AttachTo(serve)
+		cmdr.NewString("").Titles("key", "").AttachTo(serve)
+		cmdr.NewString("").Titles("token", "").AttachTo(serve)
+		cmdr.NewString("").Titles("password", "").AttachTo(serve)
+		serve.NewSubCommand("status").
+			Action(func(cmd *cmdr.Command, args []string) (err error) { return })
+		return root.RootCommand()
+	}
+
+	c := &cmdrTester{T: t, newRoot: newRoot}
+	c.expect(conformance{
+		{"serve -H h -p 1 --token t", ""},
+		{"serve -H h -p 1 --password p --cert c --key k", ""},
+		{"serve", "required flags missed: --host, --port, one of (--token --password), under command 'serve'"},
+		{"serve -p 1 --token t", "required flags missed: --host, under command 'serve'"},
+		{"serve -H h -p 1 --token t --key k", "required flags missed: --cert (together with --key)"},
+		// the groups of serve are not checked for its sub-commands,
+		// but the required flags are
+		{"serve -H h -p 1 --key k status", ""},
+		{"serve status", "required flags missed: --host, --port, under command 'status'"},
+	})
+
+	// satisfied by the environment variables and the config files
+	defer os.Unsetenv("REQUIRED_HOST")
+	_ = os.Setenv("REQUIRED_HOST", "h")
+	c.expectConfig("app:\n  serve:\n    port: 8080\n    token: t\n", conformance{
+		{"serve", ""},
+	})
+	_ = os.Unsetenv("REQUIRED_HOST")
+
+	if out := c.output("serve --help"); strings.Count(out, "[required]") != 2 {
+		t.Errorf("help: expect the required markers in:\n%v", out)
+	}
+	c.expectNoOutput("serve status --help", "[or:", "[together with:")
+}
@@ -11,10 +11,6 @@ type (
 	UnknownOptionHandler func(isFlag bool, title string, cmd *Command, args []string) (fallbackToDefaultDetector bool)
 )
 
-var (
-	unknownOptionHandler UnknownOptionHandler
-)
-
 // // SetUnknownOptionHandler enables your customized wrong command/flag processor.
 // // internal processor supports smart suggestions for those wrong commands and flags.
 // //
@@ -24,13 +20,13 @@ var (
 // }
 
 func unknownCommand(pkg *ptpkg, cmd *Command, args []string) {
-	if internalGetWorker().noUnknownCmdTip {
+	if pkg.w.noUnknownCmdTip {
 		return
 	}
 
-	ferr("\n\x1b[%dmUnknown command:\x1b[0m %v", BgBoldOrBright, pkg.a)
-	if unknownOptionHandler != nil {
-		if !unknownOptionHandler(false, pkg.a, cmd, args) {
+	pkg.w.ferr("\n\x1b[%dmUnknown command:\x1b[0m %v", BgBoldOrBright, pkg.a)
+	if pkg.w.unknownOptionHandler != nil {
+		if !pkg.w.unknownOptionHandler(false, pkg.a, cmd, args) {
 			return
 		}
 	}
@@ -38,13 +34,13 @@ func unknownCommand(pkg *ptpkg, cmd *Command, args []string) {
 }
 
 func unknownFlag(pkg *ptpkg, cmd *Command, args []string) {
-	if internalGetWorker().noUnknownCmdTip {
+	if pkg.w.noUnknownCmdTip {
 		return
 	}
 
-	ferr("\n\x1b[%dmUnknown flag:\x1b[0m %v", BgBoldOrBright, pkg.a)
-	if unknownOptionHandler != nil && !pkg.short {
-		if !unknownOptionHandler(true, pkg.a, cmd, args) {
+	pkg.w.ferr("\n\x1b[%dmUnknown flag:\x1b[0m %v", BgBoldOrBright, pkg.a)
+	if pkg.w.unknownOptionHandler != nil && !pkg.short {
+		if !pkg.w.unknownOptionHandler(true, pkg.a, cmd, args) {
 			return
 		}
 	}
@@ -55,8 +51,8 @@ func unknownCommandDetector(pkg *ptpkg, cmd *Command, args []string) {
 	ever := false
 	for k := range cmd.plainCmds {
 		distance := float64(defaultStringMetric.Calc(pkg.a, k)) / tool.StringMetricFactor
-		if distance >= internalGetWorker().similarThreshold {
-			ferr("  - do you mean: %v", k)
+		if distance >= pkg.w.similarThreshold {
+			pkg.w.ferr("  - do you mean: %v", k)
 			ever = true
 		}
 	}
@@ -84,8 +80,8 @@ func unknownFlagDetector(pkg *ptpkg, cmd *Command, args []string) {
 		str := tool.StripPrefix(pkg.a, "--")
 		for k := range cmd.plainLongFlags {
 			distance := float64(defaultStringMetric.Calc(str, k)) / tool.StringMetricFactor
-			if distance >= internalGetWorker().similarThreshold {
-				ferr("  - do you mean: --%v", k)
+			if distance >= pkg.w.similarThreshold {
+				pkg.w.ferr("  - do you mean: --%v", k)
 				ever = true
 				// } else {
 				// 	ferr("  ? '%v' - '%v': %v", pkg.a, k, distance)
@@ -215,6 +215,19 @@ func WithBuiltinCommands(versionsCmds, helpCmds, verboseCmds, generateCmds, gene
 	}
 }
 
+// WithInternalInputStream sets the internal input stream for debugging,
+// such as the scripted command-lines for the shell, see WithShellCommand,
+// or the scripted answers for the prompts, see WithPromptRequired.
+func WithInternalInputStream(in io.Reader) ExecOption {
+	return func(w *ExecWorker) {
+		w.defaultStdin = in
+		if w.defaultStdin == nil {
+			w.defaultStdin = os.Stdin
+		}
+		w.stdinReader = nil
+	}
+}
+
 // WithInternalOutputStreams sets the internal output streams for debugging
 func WithInternalOutputStreams(out, err *bufio.Writer) ExecOption {
 	return func(w *ExecWorker) {
@@ -321,7 +334,7 @@ func WithHelpTabStop(tabStop int) ExecOption {
 // internal processor supports smart suggestions for those wrong commands and flags.
 func WithUnknownOptionHandler(handler UnknownOptionHandler) ExecOption {
 	return func(w *ExecWorker) {
-		unknownOptionHandler = handler
+		w.unknownOptionHandler = handler
 	}
 }
 
@@ -405,7 +418,7 @@ func WithHelpTailLine(line string) ExecOption {
 // WithUnhandledErrorHandler handle the panics or exceptions generally
 func WithUnhandledErrorHandler(handler UnhandledErrorHandler) ExecOption {
 	return func(w *ExecWorker) {
-		unhandledErrorHandler = handler
+		w.unhandledErrorHandler = handler
 	}
 }
 
@@ -414,10 +427,6 @@ type (
 	UnhandledErrorHandler func(err interface{})
 )
 
-var (
-	unhandledErrorHandler UnhandledErrorHandler
-)
-
 // WithNoCommandAction do NOT run the action of the matched command.
 func WithNoCommandAction(b bool) ExecOption {
 	return func(w *ExecWorker) {
@@ -440,3 +449,167 @@ func WithOnPassThruCharHit(fn func(parsed *Command, switchChar string, args []st
 		w.onPassThruCharHit = fn
 	}
 }
+
+// ParsingMode is the conventions of the command-line parsing, see also
+// WithParsingMode.
+type ParsingMode int
+
+const (
+	// ParsingModeCompat is the default mode of cmdr, it accepts the
+	// non-standard forms, such as the multi-char short flags `-nv`, the
+	// values fused into the names `--nameconsul`, the toggles `-D+`,
+	// `-D-`, and the switch chars '/' and '~'.
+	ParsingModeCompat ParsingMode = iota
+	// ParsingModeGNU follows the conventions of getopt_long(3): the
+	// short flags are single-char and can be combined, such as `-abc`
+	// and `-nconsul`, the long flags take a value by `--name=consul`
+	// or `--name consul`, the next arg is always taken as the value
+	// even if it starts with '-', and '--' terminates the options.
+	// The flags and the operands can be permuted, such as
+	// `app run file -v`.
+	ParsingModeGNU
+	// ParsingModePOSIX is ParsingModeGNU without permuting, the first
+	// operand terminates the options, such as `app run file -v` gets
+	// the operands "file", "-v".
+	ParsingModePOSIX
+)
+
+// conforming reports whether it's ParsingModeGNU or ParsingModePOSIX.
+func (m ParsingMode) conforming() bool {
+	return m == ParsingModeGNU || m == ParsingModePOSIX
+}
+
+// WithParsingMode sets the conventions of the command-line parsing,
+// the default is ParsingModeCompat.
+//
+//     cmdr.Exec(rootCmd, cmdr.WithParsingMode(cmdr.ParsingModePOSIX))
+func WithParsingMode(mode ParsingMode) ExecOption {
+	return func(w *ExecWorker) {
+		w.parsingMode = mode
+	}
+}
+
+// WithResponseFiles enables the response files: an argument `@path` is
+// replaced with the arguments read from the file path before parsing.
+// The arguments in a response file are separated by the whitespaces
+// and the newlines, can be quoted in the shell style, and a word
+// starting with '#' comments out the rest of the line. A response file
+// can include the others, up to 10 levels.
+//
+//     $ cat build.args
+//     # the common options
+//     --name 'the app' -v
+//     $ app server start @build.args --port 8080
+//
+// Default is false.
+func WithResponseFiles(b bool) ExecOption {
+	return func(w *ExecWorker) {
+		w.responseFiles = b
+	}
+}
+
+// WithAbbreviations enables the abbreviations: a sub-command or a long
+// flag can be typed as any unambiguous prefix of its names, such as
+// `app serv sta --verb` for `app server start --verbose`. An ambiguous
+// prefix is an error which lists the candidates. The hidden commands
+// and flags are never abbreviated.
+//
+// In ParsingModeCompat, the unique prefix is taken before the value
+// fused into the name, that is, `--nam` is `--name` and `--nameconsul`
+// is `--name=consul` still, but `--names` is `--names-file` rather
+// than `--name=s` if both of them are defined.
+//
+// Default is false.
+func WithAbbreviations(b bool) ExecOption {
+	return func(w *ExecWorker) {
+		w.abbreviations = b
+	}
+}
+
+// WithPlugins enables the git-style plugins: an unknown command `foo`
+// of the root command runs the executable `<AppName>-foo` with the
+// remaining args, which is searched in dirs, and then on PATH. The
+// options are exported to the plugin as the env vars, such as
+// `CMDR_APP_SERVER_PORT=8080` for the key `app.server.port`, see also
+// WithEnvPrefix. The plugins are listed in the Plugins group of the
+// help screen, but a plugin can't override a builtin command.
+//
+// The error of the plugin, such as an *exec.ExitError, is returned by
+// Exec, so that the exit code can be propagated:
+//
+//     if err := cmdr.Exec(rootCmd, cmdr.WithPlugins("~/.app/plugins")); err != nil {
+//         if e, ok := err.(*exec.ExitError); ok {
+//             os.Exit(e.ExitCode())
+//         }
+//     }
+func WithPlugins(dirs ...string) ExecOption {
+	return func(w *ExecWorker) {
+		w.plugins = true
+		for _, dir := range dirs {
+			w.pluginDirs = append(w.pluginDirs, normalizeDir(dir))
+		}
+	}
+}
+
+// WithAliasCommands enables the builtin command `alias` to manage the
+// aliases in the config section `app.aliases`:
+//
+//     app alias set co 'checkout --force'
+//     app alias list
+//     app alias remove co
+//
+// The aliases are saved into the file `conf.d/aliases.yml` beside the
+// main config file. The aliases in the config files are always
+// available, such as `app co` for `app checkout --force`, even if the
+// command `alias` is disabled.
+//
+// Default is false.
+func WithAliasCommands(b bool) ExecOption {
+	return func(w *ExecWorker) {
+		w.enableAliasCommands = b
+	}
+}
+
+// WithShellCommand enables the builtin command `shell`, which runs the
+// command-lines interactively against the same root command:
+//
+//     $ app shell
+//     app> server start --port 8080
+//     app> server stop
+//     app> exit
+//
+// The Options are kept between the command-lines, but the flags are
+// reset before each one. On a terminal, the line editing, the history
+// and the tab completion are supported.
+//
+// Default is false.
+func WithShellCommand(b bool) ExecOption {
+	return func(w *ExecWorker) {
+		w.enableShellCommand = b
+	}
+}
+
+// WithPromptRequired asks for the missing required flags and positional
+// arguments interactively, instead of failing, if the input stream is a
+// terminal:
+//
+//     $ app login
+//     user name: hedzr
+//     password:
+//
+// The Description is the prompt text, the DefaultValue is taken for an
+// empty answer, and the ValidArgs are shown as a numbered list. A flag
+// with ExternalToolPasswordInput is read without echo. The prompts are
+// written to the error stream, so that `app cmd > out.txt` captures the
+// output of the command only.
+//
+// Nothing is asked if the input stream is redirected from a file or a
+// pipe. For testing, the answers can be scripted by
+// WithInternalInputStream.
+//
+// Default is false.
+func WithPromptRequired(b bool) ExecOption {
+	return func(w *ExecWorker) {
+		w.promptRequired = b
+	}
+}
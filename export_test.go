@@ -268,7 +268,7 @@ func TestUnknownXXX(t *testing.T) {
 		}
 	}
 
-	pkg = &ptpkg{}
+	pkg = &ptpkg{w: internalGetWorker()}
 	unknownCommand(pkg, cmd, args)
 	unknownFlagDetector(pkg, cmd, args)
 }
@@ -322,7 +322,7 @@ func (pkg *ptpkg) setOwner(cmd *Command) {
 
 // TestPtpkgToggleGroup functions
 func TestPtpkgToggleGroup(t *testing.T) {
-	pkg := &ptpkg{flg: &Flag{
+	pkg := &ptpkg{w: internalGetWorker(), flg: &Flag{
 		ToggleGroup: "XX",
 	}}
 	pkg.setOwner(&Command{
@@ -338,7 +338,7 @@ func TestPtpkgToggleGroup(t *testing.T) {
 
 	pkg.tryToggleGroup()
 
-	pkg = &ptpkg{flg: &Flag{
+	pkg = &ptpkg{w: internalGetWorker(), flg: &Flag{
 		DefaultValue: time.Second,
 	}}
 	_ = pkg.tryExtractingOthers([]string{}, reflect.Chan)
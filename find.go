@@ -129,6 +129,17 @@ func (w *ExecWorker) getPrefix() string {
 	return strings.Join(w.rxxtPrefixes, ".")
 }
 
+func (w *ExecWorker) wrapWithRxxtPrefix(key string) string {
+	if len(w.rxxtPrefixes) == 0 {
+		return key
+	}
+	p := w.getPrefix() // strings.Join(RxxtPrefix, ".")
+	if len(key) == 0 {
+		return p
+	}
+	return p + "." + key
+}
+
 func (w *ExecWorker) getRemainArgs(pkg *ptpkg, args []string) []string {
 	return pkg.remainArgs
 }
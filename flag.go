@@ -7,7 +7,11 @@ package cmdr
 import (
 	"fmt"
 	"github.com/hedzr/cmdr/tool"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // GetTriggeredTimes returns the matched times
@@ -96,17 +100,230 @@ func (s *Flag) GetTitleFlagNamesByMax(delimChar string, maxShort int) string {
 		sb.WriteRune(' ')
 	}
 	sb.WriteRune(' ')
-	sb.WriteString("--")
+	sb.WriteString(s.longPrefix())
 	sb.WriteString(s.Full)
-	if len(s.DefaultValuePlaceholder) > 0 {
+	if len(s.NoOptDefVal) > 0 {
+		sb.WriteString(fmt.Sprintf("[=%s]", s.placeholder()))
+	} else if len(s.DefaultValuePlaceholder) > 0 {
 		// str += fmt.Sprintf("=\x1b[2m\x1b[%dm%s\x1b[0m", DarkColor, s.DefaultValuePlaceholder)
 		sb.WriteString(fmt.Sprintf("=%s", s.DefaultValuePlaceholder))
 	}
 
 	for _, sz := range s.Aliases {
 		sb.WriteString(delimChar)
-		sb.WriteString("--")
+		sb.WriteString(s.longPrefix())
 		sb.WriteString(sz)
 	}
 	return sb.String()
 }
+
+// placeholder returns DefaultValuePlaceholder, or "VALUE" if it's empty.
+func (s *Flag) placeholder() string {
+	if len(s.DefaultValuePlaceholder) > 0 {
+		return s.DefaultValuePlaceholder
+	}
+	return "VALUE"
+}
+
+// longPrefix returns the prefix of the long names in help screen, it's
+// "--[no-]" for a Negatable flag.
+func (s *Flag) longPrefix() string {
+	if s.Negatable {
+		return "--[no-]"
+	}
+	return "--"
+}
+
+// hasRange reports whether the range [Min..Max] applies to this flag.
+// The range applies to a numeric flag only if Min < Max.
+func (s *Flag) hasRange() bool {
+	if s.Min >= s.Max {
+		return false
+	}
+	switch reflect.ValueOf(s.DefaultValue).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// inRange reports whether the numeric value v is in [Min..Max].
+func (s *Flag) inRange(v interface{}) bool {
+	if !s.hasRange() {
+		return true
+	}
+	switch x := reflect.ValueOf(v); x.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return x.Int() >= s.Min && x.Int() <= s.Max
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return s.Max >= 0 && x.Uint() <= uint64(s.Max) && (s.Min < 0 || x.Uint() >= uint64(s.Min))
+	case reflect.Float32, reflect.Float64:
+		return x.Float() >= float64(s.Min) && x.Float() <= float64(s.Max)
+	}
+	return true
+}
+
+// rangeString returns the range as "[Min..Max]", a time.Duration flag
+// shows it as "[1s..1m0s]".
+func (s *Flag) rangeString() string {
+	if _, ok := s.DefaultValue.(time.Duration); ok {
+		return fmt.Sprintf("[%v..%v]", time.Duration(s.Min), time.Duration(s.Max))
+	}
+	return fmt.Sprintf("[%v..%v]", s.Min, s.Max)
+}
+
+// isValue reports whether this flag holds a Value, see toValue.
+func (s *Flag) isValue() bool {
+	return s.value != nil
+}
+
+// newValue returns a copy of the Value of this flag, so that setting it
+// doesn't touch the DefaultValue.
+func (s *Flag) newValue() Value {
+	v := s.value
+	x := reflect.ValueOf(v)
+	if x.Kind() != reflect.Ptr || x.IsNil() {
+		return v
+	}
+	nv := reflect.New(x.Elem().Type())
+	nv.Elem().Set(x.Elem())
+	if r, ok := nv.Interface().(Value); ok {
+		return r
+	}
+	return v
+}
+
+// valueType returns the type name used in error messages.
+func (s *Flag) valueType() string {
+	if s.value != nil {
+		return s.value.Type()
+	}
+	if s.isMap() {
+		return "map"
+	}
+	if s.Counter {
+		return "count"
+	}
+	return fmt.Sprintf("%T", s.DefaultValue)
+}
+
+// isNegated reports whether the long name fn is a negated form of this
+// flag, such as "no-color" for a Negatable flag "color".
+func (s *Flag) isNegated(fn string) bool {
+	if !s.Negatable || !strings.HasPrefix(fn, "no-") {
+		return false
+	}
+	for _, sz := range s.GetLongTitleNamesArray() {
+		if sz == fn {
+			return false
+		}
+		if sz == fn[3:] {
+			return true
+		}
+	}
+	return false
+}
+
+// takesValue reports whether this flag takes a value, it's false for a
+// bool or counter flag.
+func (s *Flag) takesValue() bool {
+	return !isBool(s.DefaultValue) && !s.Counter
+}
+
+// takesNextArg reports whether this flag takes the next argument as its
+// value if no value is attached.
+func (s *Flag) takesNextArg() bool {
+	return s.takesValue() && len(s.NoOptDefVal) == 0
+}
+
+// parseCount returns the count in text for a counter flag, such as
+// "3". A bool text counts 1 for true and 0 for false.
+func parseCount(text string) (n int, err error) {
+	if n, err = strconv.Atoi(text); err == nil && n >= 0 {
+		return
+	}
+	if b, e := strconv.ParseBool(text); e == nil {
+		if b {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	return 0, fmt.Errorf("%q is not a count, such as 3", text)
+}
+
+// isMap reports whether this is a map flag, such as map[string]string
+// or map[string]int.
+func (s *Flag) isMap() bool {
+	return reflect.ValueOf(s.DefaultValue).Kind() == reflect.Map
+}
+
+// parseMap parses the text "k1=v1,k2=v2" of a map flag, the values are
+// converted to the element type of the map.
+func (s *Flag) parseMap(text string) (m map[string]interface{}, err error) {
+	m = make(map[string]interface{})
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+
+	sample := reflect.Zero(reflect.TypeOf(s.DefaultValue).Elem()).Interface()
+	for _, entry := range strings.Split(text, ",") {
+		ix := strings.Index(entry, "=")
+		if ix <= 0 {
+			err = fmt.Errorf("%q is not a key=value pair", entry)
+			return
+		}
+		k := strings.TrimSpace(entry[:ix])
+		if m[k], err = convertText(entry[ix+1:], sample); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// typedMap returns m as a map in the type of DefaultValue, such as
+// map[string]int. The values which cannot be converted are skipped.
+func (s *Flag) typedMap(m map[string]interface{}) interface{} {
+	typ := reflect.TypeOf(s.DefaultValue)
+	sample := reflect.Zero(typ.Elem()).Interface()
+	ret := reflect.MakeMapWithSize(typ, len(m))
+	for k, v := range m {
+		x := reflect.ValueOf(v)
+		if !x.IsValid() || x.Type() != typ.Elem() {
+			cv, err := convertText(fmt.Sprint(v), sample)
+			if err != nil {
+				continue
+			}
+			x = reflect.ValueOf(cv)
+		}
+		ret.SetMapIndex(reflect.ValueOf(k), x)
+	}
+	return ret.Interface()
+}
+
+// toStringKeyMap returns the map v as a map[string]interface{}.
+func toStringKeyMap(v interface{}) map[string]interface{} {
+	m := make(map[string]interface{})
+	x := reflect.ValueOf(v)
+	if x.Kind() == reflect.Map {
+		for _, k := range x.MapKeys() {
+			m[fmt.Sprint(k.Interface())] = x.MapIndex(k).Interface()
+		}
+	}
+	return m
+}
+
+// mapText returns the map v as the text "k1=v1,k2=v2", sorted by key.
+func mapText(v interface{}) string {
+	m := toStringKeyMap(v)
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for i, k := range keys {
+		keys[i] = fmt.Sprintf("%v=%v", k, m[k])
+	}
+	return strings.Join(keys, ",")
+}
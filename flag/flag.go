@@ -7,6 +7,8 @@ package flag
 
 import (
 	"github.com/hedzr/cmdr"
+	"net"
+	"net/url"
 	"reflect"
 	"time"
 )
@@ -128,13 +130,35 @@ func WithValidArgs(list ...string) (opt Option) {
 }
 
 // WithHeadLike enables `head -n` mode.
-// min, max will be ignored at this version, its might be impl in the future
+// min, max is the valid range of the value.
 func WithHeadLike(enable bool, min, max int64) (opt Option) {
 	return func(flag cmdr.OptFlag) {
 		flag.HeadLike(enable, min, max)
 	}
 }
 
+// WithRange sets the valid range [min..max] of a numeric option.
+func WithRange(min, max int64) (opt Option) {
+	return func(flag cmdr.OptFlag) {
+		flag.Range(min, max)
+	}
+}
+
+// WithNegatable enables the negated form `--no-xxx` of a bool option.
+func WithNegatable(negatable bool) (opt Option) {
+	return func(flag cmdr.OptFlag) {
+		flag.Negatable(negatable)
+	}
+}
+
+// WithOptionalValue makes the value of an option optional, such as
+// `--color[=WHEN]`, it takes noOptDefVal if it's given bare.
+func WithOptionalValue(noOptDefVal string) (opt Option) {
+	return func(flag cmdr.OptFlag) {
+		flag.OptionalValue(noOptDefVal)
+	}
+}
+
 // WithEnvKeys binds the environ variable keynames to an option.
 func WithEnvKeys(keys ...string) (opt Option) {
 	return func(flag cmdr.OptFlag) {
@@ -583,6 +607,297 @@ func IntSlice(name string, value []int, usage string, options ...Option) *[]int
 	return p
 }
 
+// TimeVar defines a time.Time flag with specified name, default value, and usage string.
+// The argument p points to a time.Time variable in which to store the value of the flag.
+// The flag accepts a value acceptable to time.Parse(layout, value), time.RFC3339 is used if layout is empty.
+func TimeVar(p *time.Time, name string, value time.Time, layout string, usage string, options ...Option) {
+	*p = value
+	f := pfRootCmd.Time(layout)
+	f.Description(usage, usage).DefaultValue(value, "")
+	if treatAsLongOpt {
+		f.Long(name)
+	} else {
+		f.Short(name)
+	}
+
+	for _, opt := range options {
+		opt(f)
+	}
+
+	f.OnSet(func(keyPath string, val interface{}) {
+		if v, ok := val.(time.Time); ok {
+			*p = v
+		}
+	})
+}
+
+// Time defines a time.Time flag with specified name, default value, and usage string.
+// The return value is the address of a time.Time variable that stores the value of the flag.
+// The flag accepts a value acceptable to time.Parse(layout, value), time.RFC3339 is used if layout is empty.
+func Time(name string, value time.Time, layout string, usage string, options ...Option) *time.Time {
+	var p = new(time.Time)
+	TimeVar(p, name, value, layout, usage, options...)
+	return p
+}
+
+// IPVar defines a net.IP flag with specified name, default value, and usage string.
+// The argument p points to a net.IP variable in which to store the value of the flag.
+// The flag accepts an IPv4 or IPv6 address.
+func IPVar(p *net.IP, name string, value net.IP, usage string, options ...Option) {
+	*p = value
+	f := pfRootCmd.IP()
+	f.Description(usage, usage).DefaultValue(value, "")
+	if treatAsLongOpt {
+		f.Long(name)
+	} else {
+		f.Short(name)
+	}
+
+	for _, opt := range options {
+		opt(f)
+	}
+
+	f.OnSet(func(keyPath string, val interface{}) {
+		if v, ok := val.(net.IP); ok {
+			*p = v
+		}
+	})
+}
+
+// IP defines a net.IP flag with specified name, default value, and usage string.
+// The return value is the address of a net.IP variable that stores the value of the flag.
+// The flag accepts an IPv4 or IPv6 address.
+func IP(name string, value net.IP, usage string, options ...Option) *net.IP {
+	var p = new(net.IP)
+	IPVar(p, name, value, usage, options...)
+	return p
+}
+
+// IPNetVar defines a *net.IPNet flag with specified name, default value, and usage string.
+// The argument p points to a *net.IPNet variable in which to store the value of the flag.
+// The flag accepts a CIDR notation, such as "192.0.2.0/24".
+func IPNetVar(p **net.IPNet, name string, value *net.IPNet, usage string, options ...Option) {
+	*p = value
+	f := pfRootCmd.IPNet()
+	f.Description(usage, usage).DefaultValue(value, "")
+	if treatAsLongOpt {
+		f.Long(name)
+	} else {
+		f.Short(name)
+	}
+
+	for _, opt := range options {
+		opt(f)
+	}
+
+	f.OnSet(func(keyPath string, val interface{}) {
+		if v, ok := val.(*net.IPNet); ok {
+			*p = v
+		}
+	})
+}
+
+// IPNet defines a *net.IPNet flag with specified name, default value, and usage string.
+// The return value is the address of a *net.IPNet variable that stores the value of the flag.
+// The flag accepts a CIDR notation, such as "192.0.2.0/24".
+func IPNet(name string, value *net.IPNet, usage string, options ...Option) **net.IPNet {
+	var p = new(*net.IPNet)
+	IPNetVar(p, name, value, usage, options...)
+	return p
+}
+
+// URLVar defines a *url.URL flag with specified name, default value, and usage string.
+// The argument p points to a *url.URL variable in which to store the value of the flag.
+// The flag accepts an absolute URL.
+func URLVar(p **url.URL, name string, value *url.URL, usage string, options ...Option) {
+	*p = value
+	f := pfRootCmd.URL()
+	f.Description(usage, usage).DefaultValue(value, "")
+	if treatAsLongOpt {
+		f.Long(name)
+	} else {
+		f.Short(name)
+	}
+
+	for _, opt := range options {
+		opt(f)
+	}
+
+	f.OnSet(func(keyPath string, val interface{}) {
+		if v, ok := val.(*url.URL); ok {
+			*p = v
+		}
+	})
+}
+
+// URL defines a *url.URL flag with specified name, default value, and usage string.
+// The return value is the address of a *url.URL variable that stores the value of the flag.
+// The flag accepts an absolute URL.
+func URL(name string, value *url.URL, usage string, options ...Option) **url.URL {
+	var p = new(*url.URL)
+	URLVar(p, name, value, usage, options...)
+	return p
+}
+
+// SizeVar defines a human readable size flag with specified name, default value, and usage string.
+// The argument p points to an uint64 variable in which to store the value of the flag in bytes.
+// The flag accepts a value such as "64MiB", "1.5GB" or "512".
+func SizeVar(p *uint64, name string, value uint64, usage string, options ...Option) {
+	*p = value
+	f := pfRootCmd.Size()
+	f.Description(usage, usage).DefaultValue(value, "")
+	if treatAsLongOpt {
+		f.Long(name)
+	} else {
+		f.Short(name)
+	}
+
+	for _, opt := range options {
+		opt(f)
+	}
+
+	f.OnSet(func(keyPath string, val interface{}) {
+		if v, ok := val.(uint64); ok {
+			*p = v
+		}
+	})
+}
+
+// Size defines a human readable size flag with specified name, default value, and usage string.
+// The return value is the address of an uint64 variable that stores the value of the flag in bytes.
+// The flag accepts a value such as "64MiB", "1.5GB" or "512".
+func Size(name string, value uint64, usage string, options ...Option) *uint64 {
+	var p = new(uint64)
+	SizeVar(p, name, value, usage, options...)
+	return p
+}
+
+// CountVar defines a counter flag with specified name, and usage string.
+// The argument p points to an int variable in which to store how many times
+// the flag is given, such as 3 for "-vvv". A count can also be given by
+// "--verbose=3" or an environment variable.
+func CountVar(p *int, name string, usage string, options ...Option) {
+	*p = 0
+	f := pfRootCmd.Counter()
+	f.Description(usage, usage).DefaultValue(0, "")
+	if treatAsLongOpt {
+		f.Long(name)
+	} else {
+		f.Short(name)
+	}
+
+	for _, opt := range options {
+		opt(f)
+	}
+
+	f.OnSet(func(keyPath string, val interface{}) {
+		if v, ok := val.(int); ok {
+			*p = v
+		}
+	})
+}
+
+// Count defines a counter flag with specified name, and usage string.
+// The return value is the address of an int variable that stores how many
+// times the flag is given.
+func Count(name string, usage string, options ...Option) *int {
+	var p = new(int)
+	CountVar(p, name, usage, options...)
+	return p
+}
+
+// Var defines a flag with the specified name and usage string. The type and
+// value of the flag are represented by the first argument, of type cmdr.Value,
+// which typically holds a user-defined implementation of cmdr.Value. For
+// instance, the caller could create a flag that turns a comma-separated string
+// into a slice of strings by giving the slice the methods of cmdr.Value; in
+// particular, Set would decompose the comma-separated string into the slice.
+func Var(value cmdr.Value, name string, usage string, options ...Option) {
+	f := pfRootCmd.Value()
+	f.Description(usage, usage).DefaultValue(value, "")
+	if treatAsLongOpt {
+		f.Long(name)
+	} else {
+		f.Short(name)
+	}
+
+	for _, opt := range options {
+		opt(f)
+	}
+
+	f.OnSet(func(keyPath string, val interface{}) {
+		// cmdr sets a copy of value, write it back
+		dst, src := reflect.ValueOf(value), reflect.ValueOf(val)
+		if dst.Kind() == reflect.Ptr && src.Type() == dst.Type() && src.Pointer() != dst.Pointer() {
+			dst.Elem().Set(src.Elem())
+		}
+	})
+}
+
+// StringMapVar defines a map[string]string flag with specified name, default value, and usage string.
+// The argument p points to a map variable in which to store the value of the flag.
+// The value is given as "k1=v1,k2=v2", and the repeated occurrences are merged.
+func StringMapVar(p *map[string]string, name string, value map[string]string, usage string, options ...Option) {
+	*p = value
+	f := pfRootCmd.StringMap()
+	f.Description(usage, usage).DefaultValue(value, "")
+	if treatAsLongOpt {
+		f.Long(name)
+	} else {
+		f.Short(name)
+	}
+
+	for _, opt := range options {
+		opt(f)
+	}
+
+	f.OnSet(func(keyPath string, val interface{}) {
+		if m, ok := val.(map[string]string); ok {
+			*p = m
+		}
+	})
+}
+
+// StringMap defines a map[string]string flag with specified name, default value, and usage string.
+// The return value is the address of a map variable that stores the value of the flag.
+func StringMap(name string, value map[string]string, usage string, options ...Option) *map[string]string {
+	var p = new(map[string]string)
+	StringMapVar(p, name, value, usage, options...)
+	return p
+}
+
+// IntMapVar defines a map[string]int flag with specified name, default value, and usage string.
+// The argument p points to a map variable in which to store the value of the flag.
+// The value is given as "k1=1,k2=2", and the repeated occurrences are merged.
+func IntMapVar(p *map[string]int, name string, value map[string]int, usage string, options ...Option) {
+	*p = value
+	f := pfRootCmd.IntMap()
+	f.Description(usage, usage).DefaultValue(value, "")
+	if treatAsLongOpt {
+		f.Long(name)
+	} else {
+		f.Short(name)
+	}
+
+	for _, opt := range options {
+		opt(f)
+	}
+
+	f.OnSet(func(keyPath string, val interface{}) {
+		if m, ok := val.(map[string]int); ok {
+			*p = m
+		}
+	})
+}
+
+// IntMap defines a map[string]int flag with specified name, default value, and usage string.
+// The return value is the address of a map variable that stores the value of the flag.
+func IntMap(name string, value map[string]int, usage string, options ...Option) *map[string]int {
+	var p = new(map[string]int)
+	IntMapVar(p, name, value, usage, options...)
+	return p
+}
+
 //
 //
 // ---------------------------------------------------------------------------
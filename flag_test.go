@@ -0,0 +1,153 @@
+// Copyright © 2020 Hedzr Yeh.
+
+package cmdr_test
+
+import (
+	"fmt"
+	"github.com/hedzr/cmdr"
+	"testing"
+)
+
+func TestMapFlags(t *testing.T) {
+	var typed interface{}
+	c := &cmdrTester{T: t}
+	c.newRoot = func() *cmdr.RootCommand {
+		root := cmdr.Root("mapped", "1.0.0")
+		cmd := root.NewSubCommand("run").Action(c.action)
+		cmdr.NewStringMap(map[string]string{"env": "dev"}).
+			Titles("label", "l").
+			EnvKeys("MAPPED_LABEL").
+			AttachTo(cmd)
+		cmdr.NewIntMap().
+			Titles("limit", "").
+			OnSet(func(keyPath string, value interface{}) { typed = value }).
+			AttachTo(cmd)
+		return root.RootCommand()
+	}
+	c.result = func(w *cmdr.ExecWorker) string {
+		opts, key := optionsOf(w, "run")
+		return fmt.Sprintf("%v %v", opts.GetMap(key("label")), opts.GetMap(key("limit")))
+	}
+
+	c.expect(conformance{
+		{"run", "map[env:dev] map[]"},
+		{"run --label a=1 -l b=2,c=3", "map[a:1 b:2 c:3] map[]"},
+		{"run --limit cpu=2 --limit mem=512,cpu=4", "map[env:dev] map[cpu:4 mem:512]"},
+		{"run --limit cpu=x", `invalid map value 'cpu=x' for option '--limit' (from command-line): "x" is not a valid int`},
+		{"run --label a", `"a" is not a key=value pair, under command 'run'`},
+	})
+	if m, ok := typed.(map[string]int); !ok || m["cpu"] != 4 || m["mem"] != 512 {
+		t.Errorf("OnSet: expect a map[string]int but got %#v", typed)
+	}
+
+	c.expectEnv("MAPPED_LABEL", "team=core,tier=1", conformance{
+		{"run", "map[team:core tier:1] map[]"},
+	})
+	c.expectEnv("MAPPED_LABEL", "team", conformance{
+		{"run", "(from env var MAPPED_LABEL)"},
+	})
+	c.expectConfig("app:\n  run:\n    label:\n      zone: eu\n    limit:\n      cpu: 8\n", conformance{
+		{"run", "map[env:dev zone:eu] map[cpu:8]"},
+		{"run --label a=b", "map[a:b] map[cpu:8]"}, // the command-line wins
+	})
+	c.expectOutput("run --help", "(default=env=dev)")
+}
+
+func TestCounterFlags(t *testing.T) {
+	c := &cmdrTester{T: t}
+	c.newRoot = func() *cmdr.RootCommand {
+		root := cmdr.Root("counted", "1.0.0")
+		cmd := root.NewSubCommand("run").Action(c.action)
+		cmdr.NewCounter().Titles("trace", "t").EnvKeys("COUNTED_TRACE").AttachTo(cmd)
+		return root.RootCommand()
+	}
+	c.result = func(w *cmdr.ExecWorker) string {
+		opts, key := optionsOf(w, "run")
+		return fmt.Sprintf("%v %q", opts.GetIntEx(key("trace")), c.args)
+	}
+
+	c.expect(conformance{
+		{"run", "0 []"},
+		{"run -t", "1 []"},
+		{"run -ttt", "3 []"},
+		{"run -t -t", "2 []"},
+		{"run --trace=3", "3 []"},
+		{"run --trace=2 -tt", "4 []"},
+		{"run --trace=false", "0 []"},
+		{"run -tt file", `2 ["file"]`},
+		{"run --trace=lots", "invalid count value 'lots' for option '--trace' (from command-line)"},
+	})
+	c.expectEnv("COUNTED_TRACE", "2", conformance{
+		{"run", "2 []"},
+		{"run -t", "1 []"}, // the command-line wins
+	})
+	c.expectEnv("COUNTED_TRACE", "-1", conformance{
+		{"run", "(from env var COUNTED_TRACE)"},
+	})
+	c.expectOutput("run --help", "-t, --trace ")
+	c.expectNoOutput("run --help", "--trace=", "(default=0)")
+}
+
+func TestNegatableFlags(t *testing.T) {
+	c := &cmdrTester{T: t}
+	c.newRoot = func() *cmdr.RootCommand {
+		root := cmdr.Root("negated", "1.0.0")
+		cmdr.NewBool(true).Titles("color", "").Description("colorful output").Negatable().AttachTo(root)
+		cmd := root.NewSubCommand("build").Action(c.action)
+		cmdr.NewBool(true).Titles("cache", "c", "cached").Description("use the build cache").Negatable().AttachTo(cmd)
+		cmdr.NewBool(false).Titles("dry-run", "n").AttachTo(cmd)
+		return root.RootCommand()
+	}
+	c.result = func(w *cmdr.ExecWorker) string {
+		opts, key := optionsOf(w, "")
+		return fmt.Sprintf("color=%v no-color=%v cache=%v",
+			opts.GetBoolEx(key("color")), opts.GetBoolEx(key("no-color")), opts.GetBoolEx(key("build.cache")))
+	}
+
+	c.expect(conformance{
+		{"build", "color=true no-color=false cache=true"},
+		{"build --no-cache", "color=true no-color=false cache=false"},
+		{"build --no-cached", "color=true no-color=false cache=false"},
+		{"build --no-cache --cache", "color=true no-color=false cache=true"},
+		{"build -c", "color=true no-color=false cache=true"},
+		{"build --no-color", "color=false no-color=true cache=true"},
+		{"--no-color build --color", "color=true no-color=true cache=true"},
+	})
+	c.expectOutput("build --help", "--[no-]cache,--[no-]cached", "--[no-]color", "--dry-run")
+	c.expectNoOutput("build --help", "--[no-]dry-run")
+	c.expectOutput("__complete build --no-", "--no-cache\t", "--no-cached\t", "--no-color\t")
+}
+
+func TestOptionalValueFlags(t *testing.T) {
+	c := &cmdrTester{T: t}
+	c.newRoot = func() *cmdr.RootCommand {
+		root := cmdr.Root("colored", "1.0.0")
+		cmd := root.NewSubCommand("ls").Action(c.action)
+		cmdr.NewString("auto").Titles("color", "").Placeholder("WHEN").
+			ValidArgs("always", "never", "auto").OptionalValue("always").AttachTo(cmd)
+		cmdr.NewInt(0).Titles("level", "l").OptionalValue("5").AttachTo(cmd)
+		cmdr.NewBool().Titles("all", "a").AttachTo(cmd)
+		return root.RootCommand()
+	}
+	c.result = func(w *cmdr.ExecWorker) string {
+		opts, key := optionsOf(w, "ls")
+		return fmt.Sprintf("%v %v %v %q",
+			opts.GetString(key("color")), opts.GetIntEx(key("level")), opts.GetBoolEx(key("all")), c.args)
+	}
+
+	c.expect(conformance{
+		{"ls", `auto 0 false []`},
+		{"ls --color", `always 0 false []`},
+		{"ls --color=never", `never 0 false []`},
+		{"ls --color file", `always 0 false ["file"]`},
+		{"ls -l", `auto 5 false []`},
+		{"ls -l=3", `auto 3 false []`},
+		{"ls -l 3", `auto 5 false ["3"]`},
+		{"ls -la", `auto 5 true []`},
+	})
+	c.expectOutput("ls --help", "--color[=WHEN]", "-l, --level[=VALUE]")
+	c.expectOutputs(conformance{
+		{"__complete ls --color=n", "--color=never\n"},
+		{"__complete ls --color n", ""},
+	})
+}
@@ -25,10 +25,22 @@ var (
 			Examples: `
 $ {{.AppName}} gen sh --bash
 			generate bash completion script
+$ {{.AppName}} gen sh --fish
+			generate fish completion script
+$ {{.AppName}} gen sh --powershell
+			generate powershell completion script
 $ {{.AppName}} gen shell --auto
 			generate shell completion script with detecting on current shell environment.
 $ {{.AppName}} gen sh
 			generate shell completion script with detecting on current shell environment.
+$ {{.AppName}} gen sh --zsh --install
+			install the zsh completion script into the system-wide site-functions directory.
+$ {{.AppName}} gen sh --bash --install --user
+			install the bash completion script into ~/.local/share/bash-completion/completions/.
+$ {{.AppName}} gen sh --fish --uninstall --user
+			remove the fish completion script from ~/.config/fish/completions/.
+$ {{.AppName}} gen sh --bash --stdout > /usr/share/bash-completion/completions/{{.AppName}}
+			print the bash completion script, for the packaging.
 $ {{.AppName}} gen man
 			generate linux manual (man page)
 $ {{.AppName}} gen doc
@@ -48,7 +60,7 @@ $ {{.AppName}} gen pdf
 				Short:       "s",
 				Full:        "shell",
 				Aliases:     []string{"sh"},
-				Description: "generate the bash/zsh auto-completion script or install it.",
+				Description: "generate the bash/zsh/fish/powershell auto-completion script or install it.",
 				Action:      genShell,
 			},
 			Flags: []*Flag{
@@ -60,6 +72,7 @@ $ {{.AppName}} gen pdf
 						Description: "generate auto completion script for Bash",
 					},
 					DefaultValue: false,
+					ToggleGroup:  "shell",
 				},
 				{
 					BaseOpt: BaseOpt{
@@ -69,6 +82,26 @@ $ {{.AppName}} gen pdf
 						Description: "generate auto completion script for Zsh",
 					},
 					DefaultValue: false,
+					ToggleGroup:  "shell",
+				},
+				{
+					BaseOpt: BaseOpt{
+						Full:        "fish",
+						Group:       "shell",
+						Description: "generate auto completion script for Fish",
+					},
+					DefaultValue: false,
+					ToggleGroup:  "shell",
+				},
+				{
+					BaseOpt: BaseOpt{
+						Full:        "powershell",
+						Aliases:     []string{"pwsh"},
+						Group:       "shell",
+						Description: "generate auto completion script for PowerShell",
+					},
+					DefaultValue: false,
+					ToggleGroup:  "shell",
 				},
 				{
 					BaseOpt: BaseOpt{
@@ -78,6 +111,7 @@ $ {{.AppName}} gen pdf
 						Description: "generate auto completion script to fit for your current env.",
 					},
 					DefaultValue: true,
+					ToggleGroup:  "shell",
 				},
 				{
 					BaseOpt: BaseOpt{
@@ -88,6 +122,52 @@ $ {{.AppName}} gen pdf
 					},
 					DefaultValue: true,
 				},
+				{
+					BaseOpt: BaseOpt{
+						Full:        "stdout",
+						Group:       "install",
+						Description: "print the completion script to stdout",
+					},
+					DefaultValue: true,
+					ToggleGroup:  "mode",
+				},
+				{
+					BaseOpt: BaseOpt{
+						Short:       "i",
+						Full:        "install",
+						Group:       "install",
+						Description: "install the completion script into the shell's completion directory",
+					},
+					DefaultValue: false,
+					ToggleGroup:  "mode",
+				},
+				{
+					BaseOpt: BaseOpt{
+						Full:        "uninstall",
+						Group:       "install",
+						Description: "remove the installed completion script",
+					},
+					DefaultValue: false,
+					ToggleGroup:  "mode",
+				},
+				{
+					BaseOpt: BaseOpt{
+						Full:        "user",
+						Group:       "install",
+						Description: "install to (or uninstall from) the per-user location instead of the system-wide one",
+					},
+					DefaultValue: false,
+				},
+				{
+					BaseOpt: BaseOpt{
+						Full:        "dir",
+						Group:       "install",
+						Description: "install to (or uninstall from) this directory",
+					},
+					DefaultValue:            "",
+					DefaultValuePlaceholder: "DIR",
+					FileCompletion:          true,
+				},
 			},
 		}, {
 			BaseOpt: BaseOpt{
@@ -5,39 +5,43 @@
 package cmdr
 
 import (
+	"bytes"
 	"fmt"
+	"gopkg.in/hedzr/errors.v2"
+	"io"
 	"io/ioutil"
 	"log"
 	"os"
 	"path"
+	"path/filepath"
 	"strings"
-	"text/template"
 )
 
 func genShell(cmd *Command, args []string) (err error) {
-	// logrus.Infof("OK gen shell. %v", *cmd)
-	w := internalGetWorker()
-	if GetBoolP(w.getPrefix(), "generate.shell.zsh") {
-		// if !GetBoolP(getPrefix(), "quiet") {
-		// 	logrus.Debugf("zsh-dump")
-		// }
-		// printHelpZsh(command, justFlags)
-
-		// not yet
-	} else if GetBoolP(w.getPrefix(), "generate.shell.bash") {
-		err = genShellBash(cmd, args)
-	} else {
+	w := cmd.worker()
+	prefix := w.wrapWithRxxtPrefix("generate.shell")
+
+	shell := ""
+	for _, s := range []string{"zsh", "bash", "fish", "powershell"} {
+		if w.rxxtOptions.GetBoolEx(prefix + "." + s) {
+			shell = s
+			break
+		}
+	}
+	if shell == "" {
 		// auto
-		// shell := os.Getenv("SHELL")
-		// if strings.HasSuffix(shell, "/bash") || GetBoolP(getPrefix(), "generate.shell.force-bash") {
-		// 	err = genShellBash(cmd, args)
-		// } else if strings.HasSuffix(shell, "/zsh") {
-		// 	// not yet
-		// }
-		err = genShellAuto(cmd, args)
-		// } else {
-		// 	_, _ = fmt.Fprint(os.Stderr, "Unknown shell. ignored.")
-		// err = genShellB(cmd, args)
+		shell = detectShell(w.rxxtOptions.GetBoolEx(prefix + ".force-bash"))
+	}
+	g := shellGenerators[shell]
+
+	root := w.rootCommand
+	switch w.rxxtOptions.GetString(prefix + ".mode") {
+	case "install":
+		err = g.install(w, w.rxxtOptions.GetBoolEx(prefix+".user"), w.rxxtOptions.GetString(prefix+".dir"))
+	case "uninstall":
+		err = g.uninstall(w, w.rxxtOptions.GetBoolEx(prefix+".user"), w.rxxtOptions.GetString(prefix+".dir"))
+	default: // stdout
+		err = g.write(root.ow, root)
 	}
 	return
 }
@@ -62,103 +66,201 @@ func findDepth(cmd *Command) (deep int) {
 // 	return
 // }
 
-func genShellAuto(cmd *Command, args []string) (err error) {
-	return
+// detectShell returns the name of the current shell from $SHELL,
+// bash is the fallback.
+func detectShell(forceBash bool) string {
+	shell := os.Getenv("SHELL")
+	switch {
+	case forceBash:
+	case strings.HasSuffix(shell, "/zsh"):
+		return "zsh"
+	case strings.HasSuffix(shell, "/fish"):
+		return "fish"
+	case shell == "" && os.Getenv("PSModulePath") != "":
+		return "powershell"
+	}
+	return "bash"
 }
 
-func genShellBash(cmd *Command, args []string) (err error) {
-	tmpl := template.New("bash.completion")
-	tmpl.Parse(`
-
-#
-
-# bash completion wrapper for {{.AppName}}
-# version: {{.Version}}
-#
-# Copyright (c) 2019-2025 Hedzr Yeh <hedzrz@gmail.com>
-#
-
-_cmdr_cmd_help_events () {
-  $* --help|grep "^  [^ \[\$\#\!/\\@\"']"|awk -F'   ' '{print $1}'|awk -F',' '{for (i=1;i<=NF;i++) print $i}'
+// shellGenerator generates the completion script for a shell, and
+// knows where to install it.
+type shellGenerator struct {
+	name  string
+	write func(out io.Writer, root *RootCommand) error
+	// fileName returns the file name of the script for an app
+	fileName func(appName string) string
+	// systemDirs are the system-wide locations, the first existing one
+	// will be used.
+	systemDirs []string
+	// userDir returns the per-user location
+	userDir func() string
+	// hint is printed after installed, %[1]v is the script file and
+	// %[2]v is its directory.
+	hint string
 }
 
-
-_cmdr_cmd_{{.AppName}}() {
-  local cmd="{{.AppName}}" cur prev words
-  _get_comp_words_by_ref cur prev words
-  if [ "$prev" != "" ]; then
-    unset 'words[${#words[@]}-1]'
-  fi
-
-  COMPREPLY=()
-  #pre=${COMP_WORDS[COMP_CWORD-1]}
-  #cur=${COMP_WORDS[COMP_CWORD]}
-
-  case "$prev" in
-    --help|--version)
-      COMPREPLY=()
-      return 0
-      ;;
-    $cmd)
-      COMPREPLY=( $(compgen -W "$(_cmdr_cmd_help_events $cmd)" -- ${cur}) )
-      return 0
-      ;;
-    *)
-      COMPREPLY=( $(compgen -W "$(_cmdr_cmd_help_events ${words[@]})" -- ${cur}) )
-      return 0
-      ;;
-  esac
-
-  #opts="--help --version -q --quiet -v --verbose --system --dest="
-  #opts="--help upgrade version deploy undeploy log ls ps start stop restart"
-  opts="--help"
-  cmds=$($cmd --help|grep "^  [^ \[\$\#\!/\\@\"']"|awk -F'   ' '{print $1}'|awk -F',' '{for (i=1;i<=NF;i++) print $i}')
-
-  COMPREPLY=( $(compgen -W "${opts} ${cmds}" -- ${cur}) )
-
-} # && complete -F _cmdr_cmd_{{.AppName}} {{.AppName}}
-
-if type complete >/dev/null 2>&1; then
-	# bash
-	complete -F _cmdr_cmd_{{.AppName}} {{.AppName}}
-else if type compdef >/dev/null 2>&1; then
-	# zsh
-	_cmdr_cmd_{{.AppName}}_zsh() { compadd $(_cmdr_cmd_{{.AppName}}); }
-	compdef _cmdr_cmd_{{.AppName}}_zsh {{.AppName}}
-fi; fi
-`)
-
-	linuxRoot := os.Getuid() == 0
-
-	for _, s := range []string{"/etc/bash_completion.d", "/usr/local/etc/bash_completion.d", "/tmp"} {
-		if FileExists(s) {
-			file := path.Join(s, cmd.root.AppName)
-			var f *os.File
-			if f, err = os.Create(file); err != nil {
-				if !linuxRoot {
-					continue
-				}
-				return
+var shellGenerators = map[string]*shellGenerator{
+	"bash": {
+		name:     "bash",
+		write:    writeBashCompletion,
+		fileName: func(appName string) string { return appName },
+		systemDirs: []string{
+			"/usr/share/bash-completion/completions",
+			"/usr/local/share/bash-completion/completions",
+			"/etc/bash_completion.d",
+			"/usr/local/etc/bash_completion.d",
+		},
+		userDir: func() string {
+			if dir := os.Getenv("BASH_COMPLETION_USER_DIR"); dir != "" {
+				return path.Join(dir, "completions")
 			}
+			return path.Join(xdgDataHome(), "bash-completion", "completions")
+		},
+		hint: "Start a new shell to enable it, or source %[1]v directly.",
+	},
+	"zsh": {
+		name:     "zsh",
+		write:    writeZshCompletion,
+		fileName: func(appName string) string { return "_" + appName },
+		systemDirs: []string{
+			"/usr/local/share/zsh/site-functions",
+			"/usr/share/zsh/site-functions",
+			"/usr/share/zsh/vendor-completions",
+		},
+		userDir: zshUserDir,
+		hint: `Make sure %[2]v is in your $fpath, for example in ~/.zshrc:
+
+    fpath=(%[2]v $fpath)
+    autoload -U compinit && compinit
+
+and start a new shell to enable it.`,
+	},
+	"fish": {
+		name:     "fish",
+		write:    writeFishCompletion,
+		fileName: func(appName string) string { return appName + ".fish" },
+		systemDirs: []string{
+			"/usr/share/fish/vendor_completions.d",
+			"/usr/local/share/fish/vendor_completions.d",
+		},
+		userDir: func() string { return path.Join(xdgConfigHome(), "fish", "completions") },
+		hint:    "Start a new fish session to enable it, or source %[1]v directly.",
+	},
+	"powershell": {
+		name:     "powershell",
+		write:    writePowershellCompletion,
+		fileName: func(appName string) string { return appName + ".ps1" },
+		// there is no system-wide location for the powershell completions
+		userDir: func() string { return path.Join(xdgConfigHome(), "powershell", "completions") },
+		hint:    "Add '. %[1]v' into your $PROFILE to enable it.",
+	},
+}
 
-			err = tmpl.Execute(f, cmd.root)
-			if err == nil {
-				fmt.Printf(`''%v generated.
-Re-login to enable the new bash completion script.
-`, file)
-			}
-			if !linuxRoot {
-				break // for non-root user, we break file-writing loop and dump scripts to console too.
-			}
+// location returns the full path of the completion script: in dir if
+// specified, or in the per-user location, or in the system-wide one.
+func (g *shellGenerator) location(appName string, user bool, dir string) (file string, err error) {
+	switch {
+	case dir != "":
+	case user || len(g.systemDirs) == 0:
+		if dir = g.userDir(); dir == "" {
+			err = errors.New("cannot determine the home directory for the %v completion, use --dir instead", g.name)
 			return
+		}
+	default:
+		dir = g.systemDirs[0]
+		for _, d := range g.systemDirs {
+			if FileExists(d) {
+				dir = d
+				break
+			}
+		}
+	}
+	file = path.Join(dir, g.fileName(appName))
+	return
+}
 
+func (g *shellGenerator) install(w *ExecWorker, user bool, dir string) (err error) {
+	root := w.rootCommand
+	var file string
+	if file, err = g.location(root.AppName, user, dir); err != nil {
+		return
+	}
+
+	var buf bytes.Buffer
+	if err = g.write(&buf, root); err != nil {
+		return
+	}
+	if err = EnsureDir(path.Dir(file)); err == nil {
+		err = ioutil.WriteFile(file, buf.Bytes(), 0644)
+	}
+	if err != nil {
+		if os.IsPermission(err) {
+			err = errors.New("cannot write %v: permission denied, try --user or --dir, or run it as root", file)
 		}
+		return
 	}
 
-	err = tmpl.Execute(os.Stdout, cmd.root)
+	w.fp("%v completion script written: %v (%v bytes)", g.name, file, buf.Len())
+	w.fp(g.hint, file, path.Dir(file))
+	return
+}
+
+func (g *shellGenerator) uninstall(w *ExecWorker, user bool, dir string) (err error) {
+	var file string
+	if file, err = g.location(w.rootCommand.AppName, user, dir); err != nil {
+		return
+	}
+	if !FileExists(file) {
+		w.fp("%v completion script not found: %v, nothing to remove", g.name, file)
+		return
+	}
+	if err = os.Remove(file); err != nil {
+		if os.IsPermission(err) {
+			err = errors.New("cannot remove %v: permission denied, run it as root", file)
+		}
+		return
+	}
+	w.fp("%v completion script removed: %v", g.name, file)
 	return
 }
 
+// xdgDataHome returns $XDG_DATA_HOME, or ~/.local/share.
+func xdgDataHome() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return dir
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return path.Join(home, ".local", "share")
+	}
+	return ""
+}
+
+// xdgConfigHome returns $XDG_CONFIG_HOME, or ~/.config.
+func xdgConfigHome() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return dir
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return path.Join(home, ".config")
+	}
+	return ""
+}
+
+// zshUserDir returns the first directory in $FPATH under the home
+// directory, or ~/.zfunc. $FPATH is exported only if the user did.
+func zshUserDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	for _, dir := range filepath.SplitList(os.Getenv("FPATH")) {
+		if strings.HasPrefix(dir, home+"/") && FileExists(dir) {
+			return dir
+		}
+	}
+	return path.Join(home, ".zfunc")
+}
+
 // // not complete
 // func genShellB(cmd *Command, args []string) (err error) {
 // 	// var sb strings.Builder
@@ -295,15 +397,15 @@ Re-login to enable the new bash completion script.
 //
 
 func genManual(command *Command, args []string) (err error) {
-	w := internalGetWorker()
+	w := command.worker()
 	painter := newManPainter()
 	prefix := strings.Join(append(w.rxxtPrefixes, "generate.manual"), ".")
 	// logrus.Debugf("OK gen manual: hit=%v", cmd.strHit)
 	// paintFromCommand(newManPainter(), &rootCommand.Command, false)
-	err = WalkAllCommands(func(cmd *Command, index int) (err error) {
+	err = walkFromCommand(&w.rootCommand.Command, 0, func(cmd *Command, index int) (err error) {
 		painter.Reset()
 
-		dir := GetStringP(prefix, "dir")
+		dir := w.rxxtOptions.GetString(prefix + ".dir")
 		if err = EnsureDir(dir); err != nil {
 			return
 		}
@@ -336,7 +438,8 @@ func genManual(command *Command, args []string) (err error) {
 //
 
 func genDoc(command *Command, args []string) (err error) {
-	prefix := strings.Join(append(internalGetWorker().rxxtPrefixes, "generate.doc"), ".")
+	w := command.worker()
+	prefix := strings.Join(append(w.rxxtPrefixes, "generate.doc"), ".")
 	// logrus.Infof("OK gen doc: hit=%v", cmd.strHit)
 	var painter Painter
 	switch command.strHit {
@@ -351,9 +454,9 @@ func genDoc(command *Command, args []string) (err error) {
 	// case "tex":
 	// 	painter = newMarkdownPainter()
 	default: // , "doc", "d"
-		if GetBoolP(prefix, "markdown") {
+		if w.rxxtOptions.GetBoolEx(prefix + ".markdown") {
 			painter = newMarkdownPainter()
-		} else if GetBoolP(prefix, "pdf") {
+		} else if w.rxxtOptions.GetBoolEx(prefix + ".pdf") {
 			painter = newMarkdownPainter()
 			// } else if GetBoolP(prefix, "tex") {
 			// 	painter = newMarkdownPainter()
@@ -363,12 +466,11 @@ func genDoc(command *Command, args []string) (err error) {
 	}
 
 	// fmt.Printf("  .  . args = [%v]\n", args)
-	w := internalGetWorker()
-	err = WalkAllCommands(func(cmd *Command, index int) (err error) {
+	err = walkFromCommand(&w.rootCommand.Command, 0, func(cmd *Command, index int) (err error) {
 		painter.Reset()
 		// fmt.Printf("  .  .  cmd = %v\n", cmd.GetTitleNames())
 
-		dir := GetStringP(prefix, "dir")
+		dir := w.rxxtOptions.GetString(prefix + ".dir")
 		if err = EnsureDir(dir); err != nil {
 			return
 		}
@@ -0,0 +1,240 @@
+// Copyright © 2020 Hedzr Yeh.
+
+package cmdr
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// writeBashCompletion writes a static bash completion script for the
+// whole command tree of root. The commands and flags tables are
+// embedded into the script, so it never invokes the app on a tab press.
+//
+// The script works with bash 3.2+ and doesn't require the
+// bash-completion package.
+func writeBashCompletion(out io.Writer, root *RootCommand) (err error) {
+	bw := bufio.NewWriter(out)
+	appName := root.AppName
+	fn := "_" + zshIdent(appName)
+
+	var cmds []*Command
+	_ = walkFromCommand(&root.Command, 0, func(cmd *Command, index int) (err error) {
+		if !isHiddenInTree(cmd) {
+			cmds = append(cmds, cmd)
+		}
+		return
+	})
+
+	_, _ = fmt.Fprintf(bw, `#!/usr/bin/env bash
+
+# bash completion wrapper for %v
+# version: %v
+#
+# generated by cmdr, DO NOT EDIT.
+#
+# Save it into /etc/bash_completion.d/ or ~/.local/share/bash-completion/completions/,
+# or source it directly from your ~/.bashrc.
+#
+
+`, appName, root.Version)
+
+	// the sub-commands table: (path, word) -> sub-path
+	_, _ = fmt.Fprintf(bw, "%v_sub() {\n  %v_next=\n  case \"$1:$2\" in\n", fn, fn)
+	for _, cmd := range cmds {
+		if cmd.owner == nil {
+			continue
+		}
+		var pats []string
+		for _, name := range cmd.GetTitleNamesArray() {
+			pats = append(pats, bashQuote(bashCmdPath(cmd.owner)+":"+name))
+		}
+		_, _ = fmt.Fprintf(bw, "    %v) %v_next=%v ;;\n", strings.Join(pats, "|"), fn, bashQuote(bashCmdPath(cmd)))
+	}
+	_, _ = fmt.Fprintf(bw, "    *) return 1 ;;\n  esac\n}\n\n")
+
+	// the words table: path -> sub-commands, flags
+	_, _ = fmt.Fprintf(bw, "%v_words() {\n  %v_dyn=\n  case \"$1\" in\n", fn, fn)
+	for _, cmd := range cmds {
+		var subs, flags []string
+		for _, cc := range cmd.SubCommands {
+			if !cc.Hidden {
+				subs = append(subs, cc.GetTitleNamesArray()...)
+			}
+		}
+		for _, flg := range visibleFlagsInChain(cmd) {
+			flags = append(flags, dashedFlagNames(flg)...)
+		}
+		_, _ = fmt.Fprintf(bw, "    %v)\n      %v_cmds=%v\n      %v_flags=%v\n",
+			bashQuote(bashCmdPath(cmd)), fn, bashQuote(strings.Join(subs, " ")), fn, bashQuote(strings.Join(flags, " ")))
+		if cmd.hasArgCandidates() {
+			_, _ = fmt.Fprintf(bw, "      %v_dyn=1\n", fn)
+		}
+		_, _ = fmt.Fprintf(bw, "      ;;\n")
+	}
+	_, _ = fmt.Fprintf(bw, "  esac\n}\n\n")
+
+	// the flags table: (path, flag) -> kind, values, optional
+	//   kind: v = a value required, e = enum values, f = a file path,
+	//         c = the dynamic values from the hidden command `__complete`
+	//   optional: the value must be attached, such as --color=always
+	_, _ = fmt.Fprintf(bw, "%v_flag() {\n  %v_kind=\n  %v_vals=\n  %v_opt=\n  case \"$1\" in\n", fn, fn, fn, fn)
+	for _, cmd := range cmds {
+		var lines []string
+		for _, flg := range visibleFlagsInChain(cmd) {
+			if !flg.takesValue() {
+				continue
+			}
+			kind, vals := "v", ""
+			if flg.Completer != nil {
+				kind = "c"
+			} else if len(flg.ValidArgs) > 0 {
+				kind, vals = "e", strings.Join(flg.ValidArgs, " ")
+			} else if flg.FileCompletion {
+				kind = "f"
+			}
+			var pats []string
+			for _, name := range dashedFlagNames(flg) {
+				pats = append(pats, bashQuote(name))
+			}
+			line := fmt.Sprintf("        %v) %v_kind=%v", strings.Join(pats, "|"), fn, kind)
+			if vals != "" {
+				line += fmt.Sprintf("; %v_vals=%v", fn, bashQuote(vals))
+			}
+			if !flg.takesNextArg() {
+				line += fmt.Sprintf("; %v_opt=1", fn)
+			}
+			lines = append(lines, line+" ;;")
+		}
+		if len(lines) > 0 {
+			_, _ = fmt.Fprintf(bw, "    %v)\n      case \"$2\" in\n%v\n      esac\n      ;;\n",
+				bashQuote(bashCmdPath(cmd)), strings.Join(lines, "\n"))
+		}
+	}
+	_, _ = fmt.Fprintf(bw, "  esac\n  [ -n \"$%v_kind\" ]\n}\n\n", fn)
+
+	_, _ = fmt.Fprintf(bw, `%[1]v_values() {
+  # $1: kind, $2: values, $3: current word, $4: prefix, $5: flag
+  case "$1" in
+    c)
+      local IFS=$'\n'
+      COMPREPLY=( $(compgen -W "$("${COMP_WORDS[0]}" %[2]v "${args[@]}" ${5:+"$5"} "$3" 2>/dev/null | cut -f1)" -P "$4" -- "$3") )
+      ;;
+    e)
+      COMPREPLY=( $(compgen -W "$2" -P "$4" -- "$3") )
+      ;;
+    f)
+      compopt -o filenames 2>/dev/null
+      COMPREPLY=( $(compgen -f -P "$4" -- "$3") )
+      ;;
+    *)
+      COMPREPLY=()
+      ;;
+  esac
+}
+
+%[1]v() {
+  local cur="${COMP_WORDS[COMP_CWORD]}" prev="${COMP_WORDS[COMP_CWORD-1]}"
+  local path="" i w
+  local -a args=()
+  local %[1]v_next %[1]v_cmds %[1]v_flags %[1]v_dyn %[1]v_kind %[1]v_vals %[1]v_opt
+
+  COMPREPLY=()
+
+  # args collects the typed words, with '--flag = value' joined
+  for ((i=1; i<COMP_CWORD; i++)); do
+    w="${COMP_WORDS[i]}"
+    case "$w" in
+      -*=*)
+        args+=("$w")
+        ;;
+      -*)
+        if %[1]v_flag "$path" "$w"; then
+          if [ "${COMP_WORDS[i+1]}" = "=" ]; then
+            ((i+2 < COMP_CWORD)) || break
+            args+=("$w=${COMP_WORDS[i+2]}")
+            ((i+=2))
+          elif [ -n "$%[1]v_opt" ]; then
+            args+=("$w")
+          else
+            ((i+1 < COMP_CWORD)) || break
+            args+=("$w" "${COMP_WORDS[i+1]}")
+            ((i++))
+          fi
+        else
+          args+=("$w")
+        fi
+        ;;
+      *)
+        args+=("$w")
+        %[1]v_sub "$path" "$w" && path="$%[1]v_next"
+        ;;
+    esac
+  done
+
+  # --flag=value, the '=' is or isn't one of COMP_WORDBREAKS
+  if [ "$cur" = "=" ] && %[1]v_flag "$path" "$prev"; then
+    %[1]v_values "$%[1]v_kind" "$%[1]v_vals" "" "" "$prev"
+    return 0
+  fi
+  if [ "$prev" = "=" ] && %[1]v_flag "$path" "${COMP_WORDS[COMP_CWORD-2]}"; then
+    %[1]v_values "$%[1]v_kind" "$%[1]v_vals" "$cur" "" "${COMP_WORDS[COMP_CWORD-2]}"
+    return 0
+  fi
+  case "$cur" in
+    -*=*)
+      if %[1]v_flag "$path" "${cur%%%%=*}"; then
+        %[1]v_values "$%[1]v_kind" "$%[1]v_vals" "${cur#*=}" "${cur%%%%=*}=" "${cur%%%%=*}"
+      fi
+      return 0
+      ;;
+  esac
+
+  # --flag value
+  if %[1]v_flag "$path" "$prev" && [ -z "$%[1]v_opt" ]; then
+    %[1]v_values "$%[1]v_kind" "$%[1]v_vals" "$cur" "" "$prev"
+    return 0
+  fi
+
+  %[1]v_words "$path"
+  case "$cur" in
+    -*)
+      COMPREPLY=( $(compgen -W "$%[1]v_flags" -- "$cur") )
+      ;;
+    *)
+      # the dynamic candidates and the sub-commands are merged
+      if [ -n "$%[1]v_dyn" ]; then
+        %[1]v_values c "" "$cur" ""
+      elif [ -z "$%[1]v_cmds" ]; then
+        %[1]v_values f "" "$cur" ""
+      fi
+      for w in $(compgen -W "$%[1]v_cmds" -- "$cur"); do
+        [[ " ${COMPREPLY[*]} " == *" $w "* ]] || COMPREPLY+=("$w")
+      done
+      ;;
+  esac
+  return 0
+}
+
+complete -F %[1]v %[3]v
+`, fn, completeCommandName, appName)
+
+	err = bw.Flush()
+	return
+}
+
+// bashCmdPath returns the dotted path of cmd, the root command is "".
+func bashCmdPath(cmd *Command) string {
+	var a []string
+	for c := cmd; c != nil && c.owner != nil; c = c.owner {
+		a = append([]string{c.GetTitleName()}, a...)
+	}
+	return strings.Join(a, ".")
+}
+
+// bashQuote returns s as a double-quoted bash string.
+func bashQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "`", "\\`", `$`, `\$`).Replace(s) + `"`
+}
@@ -0,0 +1,180 @@
+// Copyright © 2020 Hedzr Yeh.
+
+package cmdr
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// writeFishCompletion writes the `complete -c app` lines for the whole
+// command tree of root.
+func writeFishCompletion(out io.Writer, root *RootCommand) (err error) {
+	bw := bufio.NewWriter(out)
+	appName := root.AppName
+
+	_, _ = fmt.Fprintf(bw, `# fish completion for %[1]v
+# version: %[2]v
+#
+# generated by cmdr, DO NOT EDIT.
+#
+# Save it as '%[1]v.fish' into ~/.config/fish/completions/, or
+# source it directly.
+#
+
+function __fish_%[3]v_complete
+    # the dynamic candidates from the hidden command '%[4]v'
+    set -l tokens (commandline -opc)
+    command %[1]v %[4]v $tokens[2..-1] (commandline -ct) 2>/dev/null
+end
+
+function __fish_%[3]v_path
+    # the dotted path of the command being completed, such as 'ms.list'
+    set -l path ''
+    for tok in (commandline -opc)[2..-1]
+        string match -q -- '-*' $tok; and continue
+        set -l next (__fish_%[3]v_sub "$path" $tok); and set path $next
+    end
+    echo $path
+end
+
+function __fish_%[3]v_using_path
+    # the command being completed is $argv[-1], or one of its
+    # sub-commands with '-p'
+    set -l path (__fish_%[3]v_path)
+    test "$path" = "$argv[-1]"; and return
+    test "$argv[1]" = -p; and string match -q -- "$argv[-1].*" $path
+end
+
+`, appName, root.Version, zshIdent(appName), completeCommandName)
+
+	// the path table: (path, word) -> sub-path
+	_, _ = fmt.Fprintf(bw, "function __fish_%v_sub\n    switch \"$argv[1]:$argv[2]\"\n", zshIdent(appName))
+	err = walkFromCommand(&root.Command, 0, func(cmd *Command, index int) (err error) {
+		if cmd.owner == nil || isHiddenInTree(cmd) {
+			return
+		}
+		var pats []string
+		for _, name := range cmd.GetTitleNamesArray() {
+			pats = append(pats, fishQuote(bashCmdPath(cmd.owner)+":"+name))
+		}
+		_, _ = fmt.Fprintf(bw, "        case %v\n            echo %v\n", strings.Join(pats, " "), fishQuote(bashCmdPath(cmd)))
+		return
+	})
+	_, _ = fmt.Fprintf(bw, "        case '*'\n            return 1\n    end\nend\n\ncomplete -c %v -f\n", appName)
+	dyn := fishQuote("(__fish_" + zshIdent(appName) + "_complete)")
+	usingPath := "__fish_" + zshIdent(appName) + "_using_path "
+
+	err = walkFromCommand(&root.Command, 0, func(cmd *Command, index int) (err error) {
+		if isHiddenInTree(cmd) {
+			return
+		}
+
+		// cond: in cmd or its sub-commands, for the flags. exact: in cmd
+		var cond, exact string
+		if cmd.owner == nil {
+			_, _ = fmt.Fprintf(bw, "\n# %v\n", appName)
+			exact = usingPath + `""`
+		} else {
+			_, _ = fmt.Fprintf(bw, "\n# %v %v\n", appName, replaceAll(cmd.GetDottedNamePath(), ".", " "))
+			cond = usingPath + "-p " + bashCmdPath(cmd)
+			exact = usingPath + bashCmdPath(cmd)
+		}
+
+		var subNames []string
+		for _, cc := range cmd.SubCommands {
+			if !cc.Hidden {
+				subNames = append(subNames, cc.GetTitleNamesArray()...)
+			}
+		}
+		for _, cc := range cmd.SubCommands {
+			if cc.Hidden {
+				continue
+			}
+			for _, name := range cc.GetTitleNamesArray() {
+				_, _ = fmt.Fprintf(bw, "complete -c %v -n %v -a %v -d %v\n",
+					appName, fishQuote(exact), fishQuote(name), fishQuote(cc.Description))
+			}
+		}
+
+		for _, flg := range cmd.Flags {
+			if flg.Hidden {
+				continue
+			}
+			_, _ = fmt.Fprintf(bw, "complete -c %v%v\n", appName, fishFlagSpec(flg, cond, dyn))
+		}
+
+		if cmd.hasArgCandidates() {
+			if cmd.owner == nil {
+				_, _ = fmt.Fprintf(bw, "complete -c %v -a %v\n", appName, dyn)
+			} else {
+				_, _ = fmt.Fprintf(bw, "complete -c %v -n %v -a %v\n", appName, fishQuote(exact), dyn)
+			}
+		} else if len(subNames) == 0 && cmd.owner != nil {
+			_, _ = fmt.Fprintf(bw, "complete -c %v -n %v -F\n", appName, fishQuote(exact))
+		}
+		return
+	})
+	if err == nil {
+		err = bw.Flush()
+	}
+	return
+}
+
+func fishFlagSpec(flg *Flag, cond, dyn string) string {
+	var sb strings.Builder
+	if cond != "" {
+		sb.WriteString(" -n " + fishQuote(cond))
+	}
+	for _, s := range flg.GetShortTitleNamesArray() {
+		if len(s) == 1 {
+			sb.WriteString(" -s " + s)
+		} else {
+			sb.WriteString(" -o " + s)
+		}
+	}
+	for _, s := range flg.GetLongTitleNamesArray() {
+		sb.WriteString(" -l " + s)
+		if flg.Negatable {
+			sb.WriteString(" -l no-" + s)
+		}
+	}
+	if flg.takesValue() && !flg.takesNextArg() {
+		// fish completes an optional value in the form --color=always
+		if flg.Completer != nil {
+			sb.WriteString(" -f -a " + dyn)
+		} else if len(flg.ValidArgs) > 0 {
+			sb.WriteString(" -f -a " + fishQuote(strings.Join(flg.ValidArgs, " ")))
+		}
+	} else if flg.takesValue() {
+		if flg.Completer != nil {
+			sb.WriteString(" -x -a " + dyn)
+		} else if len(flg.ValidArgs) > 0 {
+			sb.WriteString(" -x -a " + fishQuote(strings.Join(flg.ValidArgs, " ")))
+		} else if isStringValued(flg.DefaultValue) {
+			sb.WriteString(" -r -F")
+		} else {
+			sb.WriteString(" -x")
+		}
+	}
+	sb.WriteString(" -d " + fishQuote(flg.Description))
+	return sb.String()
+}
+
+// isHiddenInTree reports whether cmd or one of its parents is hidden.
+func isHiddenInTree(cmd *Command) bool {
+	for c := cmd; c != nil; c = c.owner {
+		if c.Hidden {
+			return true
+		}
+	}
+	return false
+}
+
+// fishQuote returns s as a single-quoted fish string.
+func fishQuote(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
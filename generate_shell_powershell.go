@@ -0,0 +1,238 @@
+// Copyright © 2020 Hedzr Yeh.
+
+package cmdr
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// writePowershellCompletion writes a Register-ArgumentCompleter script
+// for the whole command tree of root.
+func writePowershellCompletion(out io.Writer, root *RootCommand) (err error) {
+	bw := bufio.NewWriter(out)
+	appName := root.AppName
+
+	var cmds []*Command
+	_ = walkFromCommand(&root.Command, 0, func(cmd *Command, index int) (err error) {
+		if !isHiddenInTree(cmd) {
+			cmds = append(cmds, cmd)
+		}
+		return
+	})
+
+	_, _ = fmt.Fprintf(bw, `# powershell completion for %[1]v
+# version: %[2]v
+#
+# generated by cmdr, DO NOT EDIT.
+#
+# Dot-source it from your $PROFILE:
+#
+#     . /path/to/%[1]v.ps1
+#
+
+Register-ArgumentCompleter -Native -CommandName %[3]v -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+
+`, appName, root.Version, psQuote(appName))
+
+	// "path|word" -> sub-path
+	// the keys of a hashtable literal must be unique
+	seen := make(map[string]bool)
+	_, _ = fmt.Fprintf(bw, "    $subs = @{\n")
+	for _, cmd := range cmds {
+		if cmd.owner == nil {
+			continue
+		}
+		for _, name := range cmd.GetTitleNamesArray() {
+			key := psQuote(bashCmdPath(cmd.owner) + "|" + name)
+			if !seen[key] {
+				seen[key] = true
+				_, _ = fmt.Fprintf(bw, "        %v = %v\n", key, psQuote(bashCmdPath(cmd)))
+			}
+		}
+	}
+	_, _ = fmt.Fprintf(bw, "    }\n\n")
+
+	// path -> @(name, description) of the sub-commands
+	_, _ = fmt.Fprintf(bw, "    $cmds = @{\n")
+	for _, cmd := range cmds {
+		_, _ = fmt.Fprintf(bw, "        %v = @(\n", psQuote(bashCmdPath(cmd)))
+		for _, cc := range cmd.SubCommands {
+			if cc.Hidden {
+				continue
+			}
+			for _, name := range cc.GetTitleNamesArray() {
+				_, _ = fmt.Fprintf(bw, "            ,@(%v, %v)\n", psQuote(name), psQuote(psDesc(cc.Description, name)))
+			}
+		}
+		_, _ = fmt.Fprintf(bw, "        )\n")
+	}
+	_, _ = fmt.Fprintf(bw, "    }\n\n")
+
+	// path -> @(name, description) of the flags
+	_, _ = fmt.Fprintf(bw, "    $flags = @{\n")
+	for _, cmd := range cmds {
+		_, _ = fmt.Fprintf(bw, "        %v = @(\n", psQuote(bashCmdPath(cmd)))
+		for _, flg := range visibleFlagsInChain(cmd) {
+			for _, name := range dashedFlagNames(flg) {
+				_, _ = fmt.Fprintf(bw, "            ,@(%v, %v)\n", psQuote(name), psQuote(psDesc(flg.Description, name)))
+			}
+		}
+		_, _ = fmt.Fprintf(bw, "        )\n")
+	}
+	_, _ = fmt.Fprintf(bw, "    }\n\n")
+
+	// "path|flag" -> kind of the value, see also writeBashCompletion
+	_, _ = fmt.Fprintf(bw, "    $kinds = @{\n")
+	var enums, optional []string
+	for _, cmd := range cmds {
+		for _, flg := range visibleFlagsInChain(cmd) {
+			if !flg.takesValue() {
+				continue
+			}
+			kind := "v"
+			if flg.Completer != nil {
+				kind = "c"
+			} else if len(flg.ValidArgs) > 0 {
+				kind = "e"
+			} else if flg.FileCompletion {
+				kind = "f"
+			}
+			for _, name := range dashedFlagNames(flg) {
+				key := psQuote(bashCmdPath(cmd) + "|" + name)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				_, _ = fmt.Fprintf(bw, "        %v = '%v'\n", key, kind)
+				if !flg.takesNextArg() {
+					optional = append(optional, key)
+				}
+				if kind == "e" {
+					var a []string
+					for _, v := range flg.ValidArgs {
+						a = append(a, psQuote(v))
+					}
+					enums = append(enums, fmt.Sprintf("        %v = @(%v)\n", key, strings.Join(a, ", ")))
+				}
+			}
+		}
+	}
+	_, _ = fmt.Fprintf(bw, "    }\n\n")
+
+	// "path|flag" -> the ValidArgs
+	_, _ = fmt.Fprintf(bw, "    $enums = @{\n%v    }\n\n", strings.Join(enums, ""))
+
+	// the paths of commands which have the dynamic candidates, the
+	// hidden command '__complete' returns them with the sub-commands
+	var dyn []string
+	for _, cmd := range cmds {
+		if cmd.hasArgCandidates() {
+			dyn = append(dyn, psQuote(bashCmdPath(cmd)))
+		}
+	}
+	_, _ = fmt.Fprintf(bw, "    $dyn = @(%v)\n", strings.Join(dyn, ", "))
+
+	// the "path|flag" of the flags whose value must be attached
+	_, _ = fmt.Fprintf(bw, "    $optional = @(%v)\n", strings.Join(optional, ", "))
+
+	_, _ = fmt.Fprintf(bw, `
+    $elements = @($commandAst.CommandElements | Select-Object -Skip 1 |
+        Where-Object { $_.Extent.EndOffset -lt $cursorPosition } |
+        ForEach-Object { $_.ToString() })
+
+    $path = ''
+    $pending = $null
+    foreach ($w in $elements) {
+        if ($pending) {
+            $pending = $null
+            continue
+        }
+        if ($w.StartsWith('-')) {
+            if (-not $w.Contains('=') -and $kinds.ContainsKey("$path|$w") -and $optional -notcontains "$path|$w") {
+                $pending = "$path|$w"
+            }
+            continue
+        }
+        if ($subs.ContainsKey("$path|$w")) {
+            $path = $subs["$path|$w"]
+        }
+    }
+
+    $key = $pending
+    $prefix = ''
+    $partial = $wordToComplete
+    if (-not $key -and $wordToComplete -like '-*=*') {
+        $i = $wordToComplete.IndexOf('=')
+        $key = "$path|" + $wordToComplete.Substring(0, $i)
+        $prefix = $wordToComplete.Substring(0, $i + 1)
+        $partial = $wordToComplete.Substring($i + 1)
+        if (-not $kinds.ContainsKey($key)) {
+            return
+        }
+    }
+
+    $dynamic = {
+        param($words)
+        & %[1]v %[2]v @words 2>$null | ForEach-Object {
+            $c = $_.Split([char]9)
+            $text = if ($c.Length -gt 1) { $c[1] } else { $c[0] }
+            [System.Management.Automation.CompletionResult]::new($c[0], $c[0], 'ParameterValue', $text)
+        }
+    }
+
+    if ($key) {
+        switch ($kinds[$key]) {
+            'e' {
+                $enums[$key] | Where-Object { $_ -like "$partial*" } | ForEach-Object {
+                    [System.Management.Automation.CompletionResult]::new("$prefix$_", $_, 'ParameterValue', $_)
+                }
+            }
+            'c' {
+                & $dynamic ($elements + @($wordToComplete))
+            }
+        }
+        return
+    }
+
+    if ($wordToComplete.StartsWith('-')) {
+        foreach ($f in $flags[$path]) {
+            if ($f[0] -like "$wordToComplete*") {
+                [System.Management.Automation.CompletionResult]::new($f[0], $f[0], 'ParameterName', $f[1])
+            }
+        }
+        return
+    }
+
+    if ($dyn -contains $path) {
+        & $dynamic ($elements + @($wordToComplete))
+        return
+    }
+    foreach ($c in $cmds[$path]) {
+        if ($c[0] -like "$wordToComplete*") {
+            [System.Management.Automation.CompletionResult]::new($c[0], $c[0], 'ParameterValue', $c[1])
+        }
+    }
+}
+`, psQuote(appName), completeCommandName)
+
+	err = bw.Flush()
+	return
+}
+
+// psQuote returns s as a single-quoted powershell string.
+func psQuote(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
+
+// psDesc returns the description for a CompletionResult tooltip, which
+// must not be empty.
+func psDesc(desc, name string) string {
+	if desc = strings.Join(strings.Fields(desc), " "); desc != "" {
+		return desc
+	}
+	return name
+}
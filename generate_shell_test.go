@@ -0,0 +1,475 @@
+// Copyright © 2020 Hedzr Yeh.
+
+package cmdr
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+func newShellTestRoot() *RootCommand {
+	root := &RootCommand{
+		AppName: "demo",
+		Version: "1.0.1",
+		Command: Command{
+			BaseOpt: BaseOpt{Name: "demo"},
+			Flags: []*Flag{
+				{
+					BaseOpt:      BaseOpt{Short: "y", Full: "yes", Description: "assume yes"},
+					DefaultValue: false,
+				},
+			},
+			SubCommands: []*Command{
+				{
+					BaseOpt: BaseOpt{Short: "s", Full: "server", Aliases: []string{"serve"}, Description: "server operations"},
+					Flags: []*Flag{
+						{
+							BaseOpt:                 BaseOpt{Short: "p", Full: "port", Description: "listening [tcp] port"},
+							DefaultValue:            8080,
+							DefaultValuePlaceholder: "PORT",
+						},
+						{
+							BaseOpt:      BaseOpt{Full: "level", Description: "log level"},
+							DefaultValue: "info",
+							ValidArgs:    []string{"debug", "info", "warn"},
+						},
+						{
+							BaseOpt:      BaseOpt{Full: "color", Description: "colorize the logs"},
+							DefaultValue: "auto",
+							ValidArgs:    []string{"always", "auto", "never"},
+							NoOptDefVal:  "always",
+						},
+						{
+							BaseOpt:        BaseOpt{Short: "o", Full: "output", Description: "output file"},
+							DefaultValue:   "",
+							FileCompletion: true,
+						},
+						{
+							BaseOpt:      BaseOpt{Full: "host", Description: "remote host"},
+							DefaultValue: "",
+							Completer: func(cmd *Command, partial string) []string {
+								return []string{"alpha.local", "beta.local"}
+							},
+						},
+						{
+							BaseOpt:      BaseOpt{Full: "tcp", Description: "use tcp"},
+							DefaultValue: true,
+							ToggleGroup:  "proto",
+						},
+						{
+							BaseOpt:      BaseOpt{Full: "udp", Description: "use udp"},
+							DefaultValue: false,
+							ToggleGroup:  "proto",
+						},
+					},
+					SubCommands: []*Command{
+						{
+							BaseOpt:         BaseOpt{Full: "start", Description: "start the server"},
+							TailPlaceHolder: "<host:port>",
+						},
+						{
+							BaseOpt: BaseOpt{Full: "stop", Description: "stop the server instances"},
+							Completer: func(cmd *Command, partial string) []string {
+								return []string{"i-001", "i-002", "j-001"}
+							},
+							SubCommands: []*Command{
+								{BaseOpt: BaseOpt{Full: "all", Description: "stop all the instances"}},
+							},
+						},
+					},
+				},
+				{
+					BaseOpt: BaseOpt{Full: "secret", Hidden: true},
+				},
+			},
+		},
+	}
+
+	w := NewWorker(root, WithNoLoadConfigFiles(true))
+	_ = w.buildXref(root, nil)
+	return root
+}
+
+func TestWriteZshCompletion(t *testing.T) {
+	root := newShellTestRoot()
+
+	var buf bytes.Buffer
+	if err := writeZshCompletion(&buf, root); err != nil {
+		t.Fatal(err)
+	}
+	s := buf.String()
+	t.Log(s)
+
+	for _, want := range []string{
+		"#compdef demo",
+		"_demo() {",
+		"_demo_server() {",
+		"_demo_server_start() {",
+		"'s:server operations'",
+		"'serve:server operations'",
+		"s|server|serve)",
+		"'(-p --port)--port=[listening \\[tcp\\] port]:PORT:'",
+		"'(--level)--level=[log level]:VALUE:(debug info warn)'",
+		"'(--color)--color=-[colorize the logs]::VALUE:(always auto never)'",
+		"'(--tcp --udp)--udp[use udp]'",
+		"'(-y --yes)-y[assume yes]'",
+		"'*:<host\\:port>:_files'",
+		"'(--host)--host=[remote host]:VALUE:_demo__complete'",
+		"    cmds)\n      _demo__complete && ret=0\n",
+		"_demo_server_stop_all() {",
+		"compdef _demo demo",
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("zsh completion: expect %q", want)
+		}
+	}
+	if strings.Contains(s, "secret") {
+		t.Error("zsh completion: hidden command should not be listed")
+	}
+}
+
+func TestWriteFishCompletion(t *testing.T) {
+	root := newShellTestRoot()
+
+	var buf bytes.Buffer
+	if err := writeFishCompletion(&buf, root); err != nil {
+		t.Fatal(err)
+	}
+	s := buf.String()
+	t.Log(s)
+
+	for _, want := range []string{
+		"complete -c demo -f\n",
+		"function __fish_demo_sub\n    switch \"$argv[1]:$argv[2]\"\n        case ':s' ':server' ':serve'\n            echo 'server'\n",
+		"        case 'server.stop:all'\n            echo 'server.stop.all'\n",
+		"complete -c demo -n '__fish_demo_using_path \"\"' -a 'server' -d 'server operations'\n",
+		"complete -c demo -n '__fish_demo_using_path \"\"' -a 'serve' -d 'server operations'\n",
+		"complete -c demo -s y -l yes -d 'assume yes'\n",
+		"complete -c demo -n '__fish_demo_using_path server' -a 'start' -d 'start the server'\n",
+		"complete -c demo -n '__fish_demo_using_path -p server' -l host -x -a '(__fish_demo_complete)' -d 'remote host'\n",
+		"complete -c demo -n '__fish_demo_using_path server.stop' -a '(__fish_demo_complete)'\n",
+		"complete -c demo -n '__fish_demo_using_path server.stop' -a 'all' -d 'stop all the instances'\n",
+		"complete -c demo -n '__fish_demo_using_path -p server' -l level -x -a 'debug info warn' -d 'log level'\n",
+		"complete -c demo -n '__fish_demo_using_path -p server' -l color -f -a 'always auto never' -d 'colorize the logs'\n",
+		"complete -c demo -n '__fish_demo_using_path -p server' -s p -l port -x -d 'listening [tcp] port'\n",
+		"complete -c demo -n '__fish_demo_using_path server.start' -F\n",
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("fish completion: expect %q", want)
+		}
+	}
+	if strings.Contains(s, "secret") {
+		t.Error("fish completion: hidden command should not be listed")
+	}
+}
+
+func TestWriteBashCompletion(t *testing.T) {
+	root := newShellTestRoot()
+
+	var buf bytes.Buffer
+	if err := writeBashCompletion(&buf, root); err != nil {
+		t.Fatal(err)
+	}
+	script := buf.String()
+	if strings.Contains(script, "--help|grep") || strings.Contains(script, "secret") {
+		t.Fatal("bash completion: unexpected contents")
+	}
+
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not found")
+	}
+
+	for _, tc := range []struct {
+		words []string
+		want  string
+	}{
+		{[]string{"demo", ""}, "s server serve g generate gen"},
+		{[]string{"demo", "se"}, "server serve"},
+		{[]string{"demo", "server", ""}, "start stop"},
+		{[]string{"demo", "serve", "--l"}, "--level"},
+		{[]string{"demo", "server", "--level", ""}, "debug info warn"},
+		{[]string{"demo", "server", "--level", "=", ""}, "debug info warn"},
+		{[]string{"demo", "server", "--level", "=", "d"}, "debug"},
+		{[]string{"demo", "server", "--level=i"}, "--level=info"},
+		{[]string{"demo", "server", "--color=n"}, "--color=never"},
+		{[]string{"demo", "server", "--color", "st"}, "start stop"},
+		{[]string{"demo", "server", "-p", "80", "st"}, "start stop"},
+		{[]string{"demo", "server", "--port", "=", "80", "sta"}, "start"},
+		{[]string{"demo", "server", "-o", "generate_shell_ba"}, "generate_shell_bash.go"},
+		{[]string{"demo", "-y", "server", "--tcp", "start", "generate_shell_fi"}, "generate_shell_fish.go"},
+		{[]string{"demo", "server", "--host", "b"}, "beta.local"},
+		{[]string{"demo", "server", "--host", "=", "b"}, "beta.local"},
+		{[]string{"demo", "server", "--port=80", "stop", "i"}, "i-001 i-002"},
+		{[]string{"demo", "server", "stop", ""}, "all i-001 i-002 j-001"},
+		{[]string{"demo", "server", "stop", "a"}, "all"},
+	} {
+		var sb strings.Builder
+		sb.WriteString(script)
+		// a stub of the app for the hidden command '__complete'
+		sb.WriteString(`
+demo() {
+  [ "$1" = __complete ] || return
+  shift
+  local IFS=' '
+  case "$*" in
+    "server --host b") echo beta.local ;;
+    "server --port=80 stop i") printf 'i-001\tthe first\ni-002\n' ;;
+    "server stop ") printf 'all\tstop all the instances\ni-001\ni-002\nj-001\n' ;;
+  esac
+}
+`)
+		sb.WriteString("\nCOMP_WORDS=(")
+		for _, w := range tc.words {
+			sb.WriteString(bashQuote(w) + " ")
+		}
+		sb.WriteString(")\n")
+		sb.WriteString("COMP_CWORD=" + strconv.Itoa(len(tc.words)-1) + "\n")
+		sb.WriteString("_demo\necho \"${COMPREPLY[*]}\"\n")
+
+		out, err := exec.Command(bash, "-c", sb.String()).CombinedOutput()
+		if err != nil {
+			t.Fatalf("bash completion %q: %v\n%s", tc.words, err, out)
+		}
+		if got := strings.TrimSpace(string(out)); got != tc.want {
+			t.Errorf("bash completion %q: expect %q but got %q", tc.words, tc.want, got)
+		}
+	}
+}
+
+func TestCompletionCandidates(t *testing.T) {
+	root := newShellTestRoot()
+	w := root.w
+
+	for _, tc := range []struct {
+		words []string
+		want  []string
+	}{
+		{[]string{""}, []string{"s\tserver operations", "server\tserver operations", "serve\tserver operations", "g\tgenerators for this app.", "generate\tgenerators for this app.", "gen\tgenerators for this app."}},
+		{[]string{"ser"}, []string{"server\tserver operations", "serve\tserver operations"}},
+		{[]string{"server", "st"}, []string{"start\tstart the server", "stop\tstop the server instances"}},
+		{[]string{"server", "--le"}, []string{"--level\tlog level"}},
+		{[]string{"server", "--level", ""}, []string{"debug", "info", "warn"}},
+		{[]string{"server", "--level=i"}, []string{"--level=info"}},
+		{[]string{"server", "--host", "b"}, []string{"beta.local"}},
+		{[]string{"server", "--host=a"}, []string{"--host=alpha.local"}},
+		{[]string{"server", "-p", "80", "--host", "x", "stop", "i-"}, []string{"i-001", "i-002"}},
+		{[]string{"-y", "serve", "--tcp", "stop", ""}, []string{"all\tstop all the instances", "i-001", "i-002", "j-001"}},
+	} {
+		got := w.completionCandidates(root, tc.words)
+		if strings.Join(got, "|") != strings.Join(tc.want, "|") {
+			t.Errorf("complete %q: expect %q but got %q", tc.words, tc.want, got)
+		}
+	}
+}
+
+func TestShellComplete(t *testing.T) {
+	root := newShellTestRoot()
+	w := root.w
+
+	for _, tc := range []struct {
+		line     string
+		pos      int
+		want     string
+		wantPos  int
+		wantCand []string
+	}{
+		{"ser", 3, "serve", 5, nil},
+		{"serve", 5, "serve", 5, []string{"server", "serve"}},
+		{"server st", 9, "server st", 9, []string{"start", "stop"}},
+		{"server --le", 11, "server --level ", 15, nil},
+		{"server --level=i", 16, "server --level=info ", 20, nil},
+		{"server sta -p 80", 10, "server start  -p 80", 13, nil},
+		{"x", 1, "x", 1, nil},
+	} {
+		line, pos, cand := w.shellComplete(root, tc.line, tc.pos)
+		if line != tc.want || pos != tc.wantPos || strings.Join(cand, "|") != strings.Join(tc.wantCand, "|") {
+			t.Errorf("complete %q at %v: expect %q, %v, %q but got %q, %v, %q", tc.line, tc.pos,
+				tc.want, tc.wantPos, tc.wantCand, line, pos, cand)
+		}
+	}
+}
+
+func TestCompleteCommand(t *testing.T) {
+	root := newShellTestRoot()
+
+	var out bytes.Buffer
+	w := NewWorker(root, WithNoLoadConfigFiles(true), WithInternalOutputStreams(bufio.NewWriter(&out), nil))
+	if err := w.Run([]string{"demo", completeCommandName, "server", "--unknown", "--level", "w"}); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "warn\n" {
+		t.Fatalf("__complete: expect %q but got %q", "warn\n", out.String())
+	}
+}
+
+func TestWritePowershellCompletion(t *testing.T) {
+	root := newShellTestRoot()
+
+	var buf bytes.Buffer
+	if err := writePowershellCompletion(&buf, root); err != nil {
+		t.Fatal(err)
+	}
+
+	golden := "testdata/powershell.golden"
+	if *updateGolden {
+		if err := ioutil.WriteFile(golden, buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("powershell completion differs from %v, run 'go test -run TestWritePowershellCompletion -update' to update it:\n%v", golden, buf.String())
+	}
+}
+
+func TestBuiltinCommandsIsolated(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "cmdr-isolated")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	var out1, out2 bytes.Buffer
+	w1 := NewWorker(newShellTestRoot(), WithNoLoadConfigFiles(true), WithInternalOutputStreams(bufio.NewWriter(&out1), nil))
+	if err = w1.Run([]string{"demo", "generate", "shell", "--fish", "--install", "--user", "--dir", tmp}); err != nil {
+		t.Fatal(err)
+	}
+	w2 := NewWorker(newShellTestRoot(), WithNoLoadConfigFiles(true), WithInternalOutputStreams(bufio.NewWriter(&out2), nil))
+	if err = w2.Run([]string{"demo", "generate", "shell", "--bash"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(out2.String(), "#!/usr/bin/env bash") {
+		t.Errorf("expect the bash script printed by the second worker, but got:\n%v", out2.String())
+	}
+	prefix := w2.wrapWithRxxtPrefix("generate.shell")
+	for key, want := range map[string]interface{}{
+		".fish": false, ".bash": true, ".install": false, ".stdout": true, ".user": false, ".dir": "", ".mode": "stdout",
+	} {
+		if got := w2.rxxtOptions.Get(prefix + key); got != want {
+			t.Errorf("the second worker: expect %v = %v but got %v", key, want, got)
+		}
+	}
+
+	g1, g2 := w1.rootCommand.plainCmds["generate"], w2.rootCommand.plainCmds["generate"]
+	if g1 == g2 || g1 == generatorCommands || g2 == generatorCommands {
+		t.Fatal("expect the generate commands cloned for each worker")
+	}
+	if generatorCommands.root != nil || generatorCommands.plainCmds != nil {
+		t.Error("expect generatorCommands untouched")
+	}
+	for _, flg := range g2.plainCmds["shell"].Flags {
+		if want := flg.Full == "bash"; (flg.times > 0) != want || flg.owner != g2.plainCmds["shell"] {
+			t.Errorf("the second worker: unexpected state of --%v, times=%v", flg.Full, flg.times)
+		}
+	}
+}
+
+func TestShellInstall(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "cmdr-shell")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	run := func(args ...string) string {
+		var out bytes.Buffer
+		w := NewWorker(newShellTestRoot(), WithNoLoadConfigFiles(true), WithInternalOutputStreams(bufio.NewWriter(&out), nil))
+		if err := w.Run(append([]string{"demo", "generate", "shell"}, args...)); err != nil {
+			t.Fatalf("%q: %v", args, err)
+		}
+		return out.String()
+	}
+
+	for _, tc := range []struct {
+		shell, file, head string
+	}{
+		{"--bash", "demo", "#!/usr/bin/env bash"},
+		{"--zsh", "_demo", "#compdef demo"},
+		{"--fish", "demo.fish", "# fish completion for demo"},
+		{"--powershell", "demo.ps1", "# powershell completion for demo"},
+	} {
+		dir := path.Join(tmp, "completions", tc.shell[2:])
+		file := path.Join(dir, tc.file)
+
+		out := run(tc.shell, "--install", "--dir", dir)
+		if !strings.Contains(out, "completion script written: "+file+" (") {
+			t.Errorf("%v --install: unexpected output %q", tc.shell, out)
+		}
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatalf("%v --install: %v", tc.shell, err)
+		}
+		if !strings.HasPrefix(string(b), tc.head) {
+			t.Errorf("%v --install: unexpected contents of %v", tc.shell, file)
+		}
+
+		if out = run(tc.shell, "--stdout"); out != string(b) {
+			t.Errorf("%v --stdout: the output differs from the installed script", tc.shell)
+		}
+
+		out = run(tc.shell, "--uninstall", "--dir", dir)
+		if !strings.Contains(out, "completion script removed: "+file) || FileExists(file) {
+			t.Errorf("%v --uninstall: unexpected output %q", tc.shell, out)
+		}
+		if out = run(tc.shell, "--uninstall", "--dir", dir); !strings.Contains(out, "nothing to remove") {
+			t.Errorf("%v --uninstall again: unexpected output %q", tc.shell, out)
+		}
+	}
+
+	// the per-user locations
+	defer os.Setenv("XDG_DATA_HOME", os.Getenv("XDG_DATA_HOME"))
+	defer os.Setenv("XDG_CONFIG_HOME", os.Getenv("XDG_CONFIG_HOME"))
+	defer os.Setenv("BASH_COMPLETION_USER_DIR", os.Getenv("BASH_COMPLETION_USER_DIR"))
+	_ = os.Setenv("XDG_DATA_HOME", path.Join(tmp, "share"))
+	_ = os.Setenv("XDG_CONFIG_HOME", path.Join(tmp, "config"))
+	_ = os.Unsetenv("BASH_COMPLETION_USER_DIR")
+
+	for shell, file := range map[string]string{
+		"--bash": path.Join(tmp, "share", "bash-completion", "completions", "demo"),
+		"--fish": path.Join(tmp, "config", "fish", "completions", "demo.fish"),
+	} {
+		run(shell, "--install", "--user")
+		if !FileExists(file) {
+			t.Errorf("%v --install --user: %v not found", shell, file)
+		}
+		run(shell, "--uninstall", "--user")
+		if FileExists(file) {
+			t.Errorf("%v --uninstall --user: %v not removed", shell, file)
+		}
+	}
+
+	// the choices are kept per run, even in the same worker
+	var out bytes.Buffer
+	w := NewWorker(newShellTestRoot(), WithNoLoadConfigFiles(true), WithInternalOutputStreams(bufio.NewWriter(&out), nil))
+	dir := path.Join(tmp, "completions", "again")
+	for _, args := range [][]string{{"--fish", "--install", "--dir", dir}, {}} {
+		out.Reset()
+		if err := w.Run(append([]string{"demo", "generate", "shell"}, args...)); err != nil {
+			t.Fatalf("%q: %v", args, err)
+		}
+	}
+	if mode := w.rxxtOptions.GetString(w.wrapWithRxxtPrefix("generate.shell.mode")); mode != "stdout" || out.Len() == 0 ||
+		strings.Contains(out.String(), "completion script written") {
+		t.Errorf("expect the script printed in the second run, but got mode %q:\n%v", mode, out.String())
+	}
+	for _, flg := range w.rootCommand.plainCmds["generate"].plainCmds["shell"].Flags {
+		if want := flg.Full == "auto" || flg.Full == "stdout" || flg.Full == "force-bash"; flg.DefaultValue != want && isBool(flg.DefaultValue) {
+			t.Errorf("expect the DefaultValue of --%v unchanged, but got %v", flg.Full, flg.DefaultValue)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright © 2020 Hedzr Yeh.
+
+package cmdr
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// writeZshCompletion writes a native zsh completion function `_appname`
+// for the whole command tree of root.
+func writeZshCompletion(out io.Writer, root *RootCommand) (err error) {
+	bw := bufio.NewWriter(out)
+	appName := root.AppName
+	fn := "_" + zshIdent(appName)
+
+	_, _ = fmt.Fprintf(bw, `#compdef %v
+
+# zsh completion wrapper for %v
+# version: %v
+#
+# generated by cmdr, DO NOT EDIT.
+#
+# Save it as '%v' into one directory of your $fpath, or
+# source it directly from your ~/.zshrc.
+#
+
+`, appName, appName, root.Version, fn)
+
+	_, _ = fmt.Fprintf(bw, `%[1]v__complete() {
+  # the dynamic candidates from the hidden command '%[2]v'
+  local -a w candidates
+  w=(${(z)LBUFFER})
+  [[ $LBUFFER == *' ' ]] && w+=('')
+  [[ ${w[-1]} == -*=* ]] && w=("${(@)w[1,-2]}" "${w[-1]%%%%=*}" "${w[-1]#*=}")
+  candidates=(${(f)"$(${w[1]} %[2]v "${(@)w[2,-1]}" 2>/dev/null)"})
+  candidates=("${(@)candidates//:/\\:}")
+  candidates=("${(@)candidates//$'\t'/:}")
+  _describe -t values 'values' candidates
+}
+
+`, fn, completeCommandName)
+
+	zshCommandFunc(bw, fn, fn, &root.Command)
+
+	_, _ = fmt.Fprintf(bw, `if [ "$funcstack[1]" = "%v" ]; then
+  %v "$@"
+else
+  compdef %v %v
+fi
+
+# Local Variables:
+# mode: Shell-Script
+# sh-indentation: 2
+# indent-tabs-mode: nil
+# sh-basic-offset: 2
+# End:
+# vim: ft=zsh sw=2 ts=2 et
+`, fn, fn, fn, appName)
+
+	err = bw.Flush()
+	return
+}
+
+// zshCommandFunc writes the completion function fn for cmd, and then
+// the functions of its visible sub-commands recursively.
+func zshCommandFunc(bw *bufio.Writer, appFn, fn string, cmd *Command) {
+	var subCmds []*Command
+	for _, cc := range cmd.SubCommands {
+		if !cc.Hidden {
+			subCmds = append(subCmds, cc)
+		}
+	}
+
+	_, _ = fmt.Fprintf(bw, "%v() {\n", fn)
+	_, _ = fmt.Fprintf(bw, "  local curcontext=\"$curcontext\" state line ret=1\n")
+	_, _ = fmt.Fprintf(bw, "  typeset -A opt_args\n\n")
+	_, _ = fmt.Fprintf(bw, "  _arguments -C -s \\\n")
+	for _, spec := range zshFlagSpecs(appFn, cmd) {
+		_, _ = fmt.Fprintf(bw, "    %v \\\n", spec)
+	}
+	if len(subCmds) > 0 {
+		_, _ = fmt.Fprintf(bw, "    '1: :->cmds' \\\n")
+		_, _ = fmt.Fprintf(bw, "    '*:: :->args' && ret=0\n\n")
+
+		_, _ = fmt.Fprintf(bw, "  case $state in\n")
+		_, _ = fmt.Fprintf(bw, "    cmds)\n")
+		if cmd.hasArgCandidates() {
+			// the dynamic candidates come with the sub-commands
+			_, _ = fmt.Fprintf(bw, "      %v__complete && ret=0\n", appFn)
+		} else {
+			_, _ = fmt.Fprintf(bw, "      local -a commands\n")
+			_, _ = fmt.Fprintf(bw, "      commands=(\n")
+			for _, cc := range subCmds {
+				desc := zshEscapeColon(zshQuote(cc.Description))
+				for _, name := range cc.GetTitleNamesArray() {
+					_, _ = fmt.Fprintf(bw, "        '%v:%v'\n", zshEscapeColon(zshQuote(name)), desc)
+				}
+			}
+			_, _ = fmt.Fprintf(bw, "      )\n")
+			_, _ = fmt.Fprintf(bw, "      _describe -t commands '%v commands' commands && ret=0\n", zshQuote(cmd.GetTitleName()))
+		}
+		_, _ = fmt.Fprintf(bw, "      ;;\n")
+		_, _ = fmt.Fprintf(bw, "    args)\n")
+		_, _ = fmt.Fprintf(bw, "      case $line[1] in\n")
+		for _, cc := range subCmds {
+			_, _ = fmt.Fprintf(bw, "        %v)\n", strings.Join(cc.GetTitleNamesArray(), "|"))
+			_, _ = fmt.Fprintf(bw, "          %v && ret=0\n", zshSubFuncName(fn, cc))
+			_, _ = fmt.Fprintf(bw, "          ;;\n")
+		}
+		_, _ = fmt.Fprintf(bw, "      esac\n")
+		_, _ = fmt.Fprintf(bw, "      ;;\n")
+		_, _ = fmt.Fprintf(bw, "  esac\n\n")
+	} else if cmd.hasArgCandidates() {
+		_, _ = fmt.Fprintf(bw, "    '*:%v:%v__complete' && ret=0\n\n", zshEscapeColon(zshQuote(cmd.tailPlaceHolder())), appFn)
+	} else if len(cmd.tailPlaceHolder()) > 0 {
+		_, _ = fmt.Fprintf(bw, "    '*:%v:_files' && ret=0\n\n", zshEscapeColon(zshQuote(cmd.tailPlaceHolder())))
+	} else {
+		_, _ = fmt.Fprintf(bw, "    '*: :_files' && ret=0\n\n")
+	}
+	_, _ = fmt.Fprintf(bw, "  return ret\n}\n\n")
+
+	for _, cc := range subCmds {
+		zshCommandFunc(bw, appFn, zshSubFuncName(fn, cc), cc)
+	}
+}
+
+func zshSubFuncName(parentFn string, cc *Command) string {
+	return parentFn + "_" + zshIdent(cc.GetTitleName())
+}
+
+// zshFlagSpecs builds the _arguments specs for the flags of cmd and of
+// its parents, since the parent options are acceptable in a sub-command.
+func zshFlagSpecs(appFn string, cmd *Command) (specs []string) {
+	seen := make(map[string]bool)
+	for c := cmd; c != nil; c = c.owner {
+		for _, flg := range c.Flags {
+			if flg.Hidden || seen[flg.GetTitleName()] {
+				continue
+			}
+			seen[flg.GetTitleName()] = true
+			specs = append(specs, zshFlagSpec(appFn, flg, c)...)
+		}
+	}
+	return
+}
+
+// zshFlagSpec returns one _arguments spec for each name of flg.
+// The names of flg, and the names of the other flags in the same
+// ToggleGroup, are mutually exclusive.
+func zshFlagSpec(appFn string, flg *Flag, owner *Command) (specs []string) {
+	var exclusions []string
+	if flg.ToggleGroup != "" {
+		for _, f := range owner.Flags {
+			if f.ToggleGroup == flg.ToggleGroup {
+				exclusions = append(exclusions, dashedFlagNames(f)...)
+			}
+		}
+	} else {
+		exclusions = dashedFlagNames(flg)
+	}
+
+	desc := zshQuote(zshEscapeBrackets(flg.Description))
+	var arg string
+	if flg.takesValue() {
+		msg := flg.DefaultValuePlaceholder
+		if msg == "" {
+			msg = "VALUE"
+		}
+		arg = ":" + zshEscapeColon(zshQuote(msg)) + ":"
+		if !flg.takesNextArg() {
+			arg = ":" + arg
+		}
+		if flg.Completer != nil {
+			arg += appFn + "__complete"
+		} else if len(flg.ValidArgs) > 0 {
+			var a []string
+			for _, v := range flg.ValidArgs {
+				a = append(a, zshEscapeValue(zshQuote(v)))
+			}
+			arg += "(" + strings.Join(a, " ") + ")"
+		} else if flg.DefaultValuePlaceholder == "DIR" {
+			arg += "_files -/"
+		} else if isStringValued(flg.DefaultValue) {
+			arg += "_files"
+		}
+	}
+
+	for _, name := range dashedFlagNames(flg) {
+		if flg.Counter {
+			// a counter is repeatable, such as -vvv
+			specs = append(specs, fmt.Sprintf("'*%v[%v]'", name, desc))
+			continue
+		}
+		var suffix string
+		if arg != "" {
+			if !flg.takesNextArg() {
+				// an optional value must be attached, such as --color=always
+				if strings.HasPrefix(name, "--") {
+					suffix = "=-"
+				} else {
+					suffix = "-"
+				}
+			} else if strings.HasPrefix(name, "--") {
+				suffix = "="
+			} else {
+				suffix = "+"
+			}
+		}
+		specs = append(specs, fmt.Sprintf("'(%v)%v%v[%v]%v'",
+			strings.Join(exclusions, " "), name, suffix, desc, arg))
+	}
+	return
+}
+
+// isStringValued reports whether a flag with default value v takes a
+// free-form text, such as a file name.
+func isStringValued(v interface{}) bool {
+	switch v.(type) {
+	case nil, string, []string:
+		return true
+	}
+	return false
+}
+
+// zshIdent converts s to a valid shell function name part.
+func zshIdent(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+// zshQuote escapes s for embedding into a single-quoted string.
+func zshQuote(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.Replace(s, "'", `'\''`, -1)
+}
+
+func zshEscapeColon(s string) string {
+	return strings.Replace(s, ":", `\:`, -1)
+}
+
+func zshEscapeBrackets(s string) string {
+	return strings.NewReplacer(`[`, `\[`, `]`, `\]`).Replace(s)
+}
+
+func zshEscapeValue(s string) string {
+	return strings.NewReplacer(` `, `\ `, `:`, `\:`, `(`, `\(`, `)`, `\)`).Replace(s)
+}
@@ -10,9 +10,11 @@ import (
 	"fmt"
 	"github.com/hedzr/cmdr"
 	"github.com/hedzr/cmdr/tool"
+	"gopkg.in/hedzr/errors.v2"
 	"io/ioutil"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -33,6 +35,185 @@ func prepareStreams() (outX, errX *bytes.Buffer) {
 	return
 }
 
+// newTestWorker returns a new worker of root, and its captured outputs.
+// The config files aren't loaded unless opts asks for.
+func newTestWorker(root *cmdr.RootCommand, opts ...cmdr.ExecOption) (w *cmdr.ExecWorker, outX, errX *bytes.Buffer) {
+	outX, errX = &bytes.Buffer{}, &bytes.Buffer{}
+	w = cmdr.NewWorker(root, append([]cmdr.ExecOption{
+		cmdr.WithNoLoadConfigFiles(true),
+		cmdr.WithInternalOutputStreams(bufio.NewWriter(outX), bufio.NewWriter(errX)),
+	}, opts...)...)
+	return
+}
+
+// runWorker runs the command-line args, the app name first, by a new
+// worker of root, see newTestWorker.
+func runWorker(root *cmdr.RootCommand, args []string, opts ...cmdr.ExecOption) (w *cmdr.ExecWorker, out, errOut string, err error) {
+	w, outX, errX := newTestWorker(root, opts...)
+	err = w.Run(args)
+	return w, outX.String(), errX.String(), err
+}
+
+// isCmdrError reports whether err is a cmdr error which isn't ignorable,
+// and its message contains want.
+func isCmdrError(err error, want string) bool {
+	var perr *cmdr.ErrorForCmdr
+	return errors.As(err, &perr) && !perr.Ignorable && strings.Contains(err.Error(), want)
+}
+
+// none is the result of a command-line whose action isn't invoked.
+const none = "-"
+
+// conformance is a table of the command-lines, and the expected results,
+// or the expected error messages of them.
+type conformance []struct {
+	args string
+	want string
+}
+
+// cmdrTester runs the command-lines by the new workers of a root command,
+// and checks the results of them.
+type cmdrTester struct {
+	*testing.T
+	newRoot func() *cmdr.RootCommand
+	// result returns the values of a command-line to check, or the
+	// output is checked if it's nil.
+	result func(w *cmdr.ExecWorker) string
+	opts   []cmdr.ExecOption
+	// split splits a command-line of the conformance table, by the
+	// spaces if it's nil.
+	split func(args string) []string
+
+	cmd  *cmdr.Command // the command invoked by the last run, see action
+	args []string      // the args of cmd
+}
+
+// action is an Action which records the invoked command and its args.
+func (c *cmdrTester) action(cmd *cmdr.Command, args []string) (err error) {
+	c.cmd, c.args = cmd, append([]string{}, args...)
+	return
+}
+
+// run runs the command-line args, the app name is prepended, by a new
+// worker of the root command, see runWorker.
+func (c *cmdrTester) run(args ...string) (got, out string, err error) {
+	c.cmd, c.args = nil, nil
+	root := c.newRoot()
+	var w *cmdr.ExecWorker
+	w, out, _, err = runWorker(root, append([]string{root.AppName}, args...), c.opts...)
+	if got = out; c.result != nil {
+		got = c.result(w)
+	}
+	return
+}
+
+// expect runs the command-lines of table, and checks their results, or
+// their error messages. An empty want expects no error and no result.
+func (c *cmdrTester) expect(table conformance) {
+	c.Helper()
+	split := c.split
+	if split == nil {
+		split = func(args string) []string { return strings.Split(args, " ") }
+	}
+	for _, tc := range table {
+		got, _, err := c.run(split(tc.args)...)
+		if err != nil {
+			if tc.want == "" || !isCmdrError(err, tc.want) {
+				c.Errorf("%q: expect %q but got error %v", tc.args, tc.want, err)
+			}
+		} else if got != tc.want {
+			c.Errorf("%q: expect %q but got %q", tc.args, tc.want, got)
+		}
+	}
+}
+
+// expectEnv is expect while the environment variable key is value.
+func (c *cmdrTester) expectEnv(key, value string, table conformance) {
+	c.Helper()
+	defer os.Unsetenv(key)
+	_ = os.Setenv(key, value)
+	c.expect(table)
+}
+
+// expectConfig is expect with a config file of yml, which is loaded by
+// the option --config.
+func (c *cmdrTester) expectConfig(yml string, table conformance) {
+	c.Helper()
+	f, err := ioutil.TempFile("", "cmdr-test-*.yml")
+	if err != nil {
+		c.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(yml)
+	if e := f.Close(); err == nil {
+		err = e
+	}
+	if err != nil {
+		c.Fatal(err)
+	}
+
+	opts, split := c.opts, c.split
+	defer func() { c.opts, c.split = opts, split }()
+	c.opts = append(append([]cmdr.ExecOption{}, opts...), cmdr.WithNoLoadConfigFiles(false))
+	c.split = func(args string) []string {
+		return append([]string{"--config", f.Name()}, strings.Split(args, " ")...)
+	}
+	c.expect(table)
+}
+
+// expectOutputs is expect which checks the outputs, such as the
+// completions, rather than the results.
+func (c *cmdrTester) expectOutputs(table conformance) {
+	c.Helper()
+	result := c.result
+	defer func() { c.result = result }()
+	c.result = nil
+	c.expect(table)
+}
+
+// output runs the command-line args, and returns its output.
+func (c *cmdrTester) output(args string) string {
+	c.Helper()
+	_, out, err := c.run(strings.Split(args, " ")...)
+	if err != nil {
+		c.Fatalf("%q: %v", args, err)
+	}
+	return out
+}
+
+// expectOutput runs the command-line args, and checks that its output,
+// such as a help screen, contains each of want.
+func (c *cmdrTester) expectOutput(args string, want ...string) {
+	c.Helper()
+	out := c.output(args)
+	for _, s := range want {
+		if !strings.Contains(out, s) {
+			c.Errorf("%q: expect %q in:\n%v", args, s, out)
+		}
+	}
+}
+
+// expectNoOutput runs the command-line args, and checks that its output
+// contains none of unwanted.
+func (c *cmdrTester) expectNoOutput(args string, unwanted ...string) {
+	c.Helper()
+	out := c.output(args)
+	for _, s := range unwanted {
+		if strings.Contains(out, s) {
+			c.Errorf("%q: unexpected %q in:\n%v", args, s, out)
+		}
+	}
+}
+
+// optionsOf returns the options of w, and a func which returns the key
+// path of an option of the command cmdPath, such as "server.start".
+func optionsOf(w *cmdr.ExecWorker, cmdPath string) (opts *cmdr.Options, key func(k string) string) {
+	if cmdPath != "" {
+		cmdPath += "."
+	}
+	return w.GetOptions(), func(k string) string { return w.WrapWithRxxtPrefix(cmdPath + k) }
+}
+
 func prepareConfD(t *testing.T) func() {
 	cmdr.SetPredefinedLocationsForTesting("./.tmp.yaml")
 
@@ -10,11 +10,9 @@ import (
 // Match try parsing the input command-line, the result is the last hit *Command.
 func Match(inputCommandlineWithoutArg0 string, opts ...ExecOption) (last *Command, err error) {
 	saved := internalGetWorker()
-	savedUnknownOptionHandler := unknownOptionHandler
 	defer func() {
 		uniqueWorkerLock.Lock()
 		uniqueWorker = saved
-		unknownOptionHandler = savedUnknownOptionHandler
 		uniqueWorkerLock.Unlock()
 	}()
 
@@ -29,7 +27,7 @@ func Match(inputCommandlineWithoutArg0 string, opts ...ExecOption) (last *Comman
 	w.noDefaultHelpScreen = true
 	w.noUnknownCmdTip = true
 	w.noCommandAction = true
-	unknownOptionHandler = emptyUnknownOptionHandler
+	w.unknownOptionHandler = emptyUnknownOptionHandler
 
 	line := os.Args[0] + " " + inputCommandlineWithoutArg0
 	last, err = w.InternalExecFor(rootCmd, strings.Split(line, " "))
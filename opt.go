@@ -54,10 +54,16 @@ type (
 		Placeholder(placeholder string) (opt OptFlag)
 		ExternalTool(envKeyName string) (opt OptFlag)
 		ValidArgs(list ...string) (opt OptFlag)
+		// FileCompletion hints the shell completion to complete the value with file names
+		FileCompletion(b bool) (opt OptFlag)
+		// Completer provides the dynamic candidates of the value for the shell completions
+		Completer(completer Completer) (opt OptFlag)
 		// HeadLike enables `head -n` mode.
-		// 'min', 'max' will be ignored at this version, its might be impl in the future.
+		// 'min', 'max' is the valid range of the value, see also Range.
 		// There's only one head-like flag in one command and its parent and children commands.
 		HeadLike(enable bool, min, max int64) (opt OptFlag)
+		// Range sets the valid range [min..max] of a numeric flag.
+		Range(min, max int64) (opt OptFlag)
 
 		// EnvKeys is a list of env-var names of binding on this flag
 		EnvKeys(keys ...string) (opt OptFlag)
@@ -67,6 +73,12 @@ type (
 		//
 		//   Required() set the required flag to true while it's invoked with empty params.
 		Required(required ...bool) (opt OptFlag)
+		// Negatable enables the negated forms of a bool flag, such as
+		// `--no-color` for `--color`.
+		Negatable(negatable ...bool) (opt OptFlag)
+		// OptionalValue makes the value optional, such as `--color[=WHEN]`,
+		// the flag takes noOptDefVal if it's given bare.
+		OptionalValue(noOptDefVal string) (opt OptFlag)
 
 		OwnerCommand() (opt OptCmd)
 		SetOwner(opt OptCmd)
@@ -122,6 +134,20 @@ type (
 		PostAction(post Invoker) (opt OptCmd)
 
 		TailPlaceholder(placeholder string) (opt OptCmd)
+		// Completer provides the dynamic candidates of the positional arguments for the shell completions
+		Completer(completer Completer) (opt OptCmd)
+		// PositionalArgs declares the positional arguments, see also Command.Args
+		PositionalArgs(args ...*PositionalArg) (opt OptCmd)
+		// RequiredTogether adds a group of flags which must be given together, by their long titles
+		RequiredTogether(flags ...string) (opt OptCmd)
+		// AtLeastOneOf adds a group of flags, at least one of them is required
+		AtLeastOneOf(flags ...string) (opt OptCmd)
+		// Exclusive adds a group of mutually exclusive flags, by their long titles
+		Exclusive(flags ...string) (opt OptCmd)
+		// Requires declares that flag requires the others, by their long titles
+		Requires(flag string, requires ...string) (opt OptCmd)
+		// ConflictsWith declares that flag conflicts with the others, by their long titles
+		ConflictsWith(flag string, conflicts ...string) (opt OptCmd)
 
 		// NewFlag create a new flag object and return it for further operations.
 		// Deprecated since v1.6.9, replace it with FlagV(defaultValue)
@@ -212,6 +238,12 @@ const (
 	OptFlagTypeDuration OptFlagType = iota + 17
 	// OptFlagTypeHumanReadableSize to create a new human readable size flag
 	OptFlagTypeHumanReadableSize OptFlagType = iota + 18
+	// OptFlagTypeStringMap to create a new map[string]string flag
+	OptFlagTypeStringMap OptFlagType = iota + 19
+	// OptFlagTypeIntMap to create a new map[string]int flag
+	OptFlagTypeIntMap OptFlagType = iota + 20
+	// OptFlagTypeCounter to create a new counter flag, such as -vvv
+	OptFlagTypeCounter OptFlagType = iota + 21
 )
 
 type optContext struct {
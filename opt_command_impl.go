@@ -153,6 +153,48 @@ func (s *optCommandImpl) TailPlaceholder(placeholder string) (opt OptCmd) {
 	return
 }
 
+func (s *optCommandImpl) Completer(completer Completer) (opt OptCmd) {
+	s.working.Completer = completer
+	opt = s
+	return
+}
+
+func (s *optCommandImpl) PositionalArgs(args ...*PositionalArg) (opt OptCmd) {
+	s.working.Args = append(s.working.Args, args...)
+	opt = s
+	return
+}
+
+func (s *optCommandImpl) RequiredTogether(flags ...string) (opt OptCmd) {
+	s.working.RequiredTogether = append(s.working.RequiredTogether, flags)
+	opt = s
+	return
+}
+
+func (s *optCommandImpl) AtLeastOneOf(flags ...string) (opt OptCmd) {
+	s.working.AtLeastOneOf = append(s.working.AtLeastOneOf, flags)
+	opt = s
+	return
+}
+
+func (s *optCommandImpl) Exclusive(flags ...string) (opt OptCmd) {
+	s.working.Exclusive = append(s.working.Exclusive, flags)
+	opt = s
+	return
+}
+
+func (s *optCommandImpl) Requires(flag string, requires ...string) (opt OptCmd) {
+	s.working.Requires = append(s.working.Requires, append([]string{flag}, requires...))
+	opt = s
+	return
+}
+
+func (s *optCommandImpl) ConflictsWith(flag string, conflicts ...string) (opt OptCmd) {
+	s.working.ConflictsWith = append(s.working.ConflictsWith, append([]string{flag}, conflicts...))
+	opt = s
+	return
+}
+
 func (s *optCommandImpl) Bool() (opt OptFlag) {
 	flg := &Flag{}
 	s.working.Flags = uniAddFlg(s.working.Flags, flg)
@@ -177,6 +219,18 @@ func (s *optCommandImpl) IntSlice() (opt OptFlag) {
 	return &intSliceOpt{optFlagImpl: optFlagImpl{working: flg, parent: s}}
 }
 
+func (s *optCommandImpl) StringMap() (opt OptFlag) {
+	flg := &Flag{}
+	s.working.Flags = uniAddFlg(s.working.Flags, flg)
+	return &stringMapOpt{optFlagImpl: optFlagImpl{working: flg, parent: s}}
+}
+
+func (s *optCommandImpl) IntMap() (opt OptFlag) {
+	flg := &Flag{}
+	s.working.Flags = uniAddFlg(s.working.Flags, flg)
+	return &intMapOpt{optFlagImpl: optFlagImpl{working: flg, parent: s}}
+}
+
 func (s *optCommandImpl) Int() (opt OptFlag) {
 	flg := &Flag{}
 	s.working.Flags = uniAddFlg(s.working.Flags, flg)
@@ -231,6 +285,51 @@ func (s *optCommandImpl) Duration() (opt OptFlag) {
 	return &durationOpt{optFlagImpl: optFlagImpl{working: flg, parent: s}}
 }
 
+func (s *optCommandImpl) Value() (opt OptFlag) {
+	flg := &Flag{}
+	s.working.Flags = uniAddFlg(s.working.Flags, flg)
+	return &valueOpt{optFlagImpl: optFlagImpl{working: flg, parent: s}}
+}
+
+func (s *optCommandImpl) Time(layout string) (opt OptFlag) {
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	flg := &Flag{value: &timeValue{layout: layout}}
+	s.working.Flags = uniAddFlg(s.working.Flags, flg)
+	return &valueOpt{optFlagImpl: optFlagImpl{working: flg, parent: s}}
+}
+
+func (s *optCommandImpl) IP() (opt OptFlag) {
+	flg := &Flag{value: &ipValue{}}
+	s.working.Flags = uniAddFlg(s.working.Flags, flg)
+	return &valueOpt{optFlagImpl: optFlagImpl{working: flg, parent: s}}
+}
+
+func (s *optCommandImpl) IPNet() (opt OptFlag) {
+	flg := &Flag{value: &ipNetValue{}}
+	s.working.Flags = uniAddFlg(s.working.Flags, flg)
+	return &valueOpt{optFlagImpl: optFlagImpl{working: flg, parent: s}}
+}
+
+func (s *optCommandImpl) URL() (opt OptFlag) {
+	flg := &Flag{value: &urlValue{}}
+	s.working.Flags = uniAddFlg(s.working.Flags, flg)
+	return &valueOpt{optFlagImpl: optFlagImpl{working: flg, parent: s}}
+}
+
+func (s *optCommandImpl) Size() (opt OptFlag) {
+	flg := &Flag{value: &sizeValue{}}
+	s.working.Flags = uniAddFlg(s.working.Flags, flg)
+	return &sizeOpt{optFlagImpl: optFlagImpl{working: flg, parent: s}}
+}
+
+func (s *optCommandImpl) Counter() (opt OptFlag) {
+	flg := &Flag{Counter: true}
+	s.working.Flags = uniAddFlg(s.working.Flags, flg)
+	return &counterOpt{optFlagImpl: optFlagImpl{working: flg, parent: s}}
+}
+
 func (s *optCommandImpl) NewFlag(typ OptFlagType) (opt OptFlag) {
 	var flg OptFlag
 
@@ -249,6 +348,12 @@ func (s *optCommandImpl) NewFlag(typ OptFlagType) (opt OptFlag) {
 		flg = s.StringSlice()
 	case OptFlagTypeIntSlice:
 		flg = s.IntSlice()
+	case OptFlagTypeStringMap:
+		flg = s.StringMap()
+		flg.DefaultValue(make(map[string]string), "")
+	case OptFlagTypeIntMap:
+		flg = s.IntMap()
+		flg.DefaultValue(make(map[string]int), "")
 	case OptFlagTypeFloat32:
 		flg = s.Float32()
 	case OptFlagTypeFloat64:
@@ -259,6 +364,12 @@ func (s *optCommandImpl) NewFlag(typ OptFlagType) (opt OptFlag) {
 		flg = s.Complex128()
 	case OptFlagTypeDuration:
 		flg = s.Duration()
+	case OptFlagTypeHumanReadableSize:
+		flg = s.Size()
+		flg.DefaultValue(uint64(0), "")
+	case OptFlagTypeCounter:
+		flg = s.Counter()
+		flg.DefaultValue(0, "")
 	default:
 		flg = s.Bool()
 	}
@@ -270,6 +381,9 @@ func (s *optCommandImpl) NewFlag(typ OptFlagType) (opt OptFlag) {
 }
 
 func (s *optCommandImpl) newFlagVC(vv reflect.Type, defaultValue interface{}) (flg OptFlag) {
+	if _, ok := defaultValue.(Value); ok {
+		return s.Value()
+	}
 	switch vv.Kind() {
 	case reflect.Int, reflect.Int16, reflect.Int32:
 		flg = s.Int()
@@ -287,6 +401,12 @@ func (s *optCommandImpl) newFlagVC(vv reflect.Type, defaultValue interface{}) (f
 		flg = s.String()
 	case reflect.Slice:
 		flg = s.newFlagVCSlice(vv.Elem(), defaultValue)
+	case reflect.Map:
+		if isTypeSInt(vv.Elem().Kind()) {
+			flg = s.IntMap()
+		} else {
+			flg = s.StringMap()
+		}
 	case reflect.Float32:
 		flg = s.Float32()
 	case reflect.Float64:
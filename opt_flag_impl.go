@@ -131,6 +131,7 @@ func (s *optFlagImpl) ToggleGroup(group string) (opt OptFlag) {
 func (s *optFlagImpl) DefaultValue(val interface{}, placeholder string) (opt OptFlag) {
 	s.working.DefaultValue = val
 	s.working.DefaultValuePlaceholder = placeholder
+	s.working.value = toValue(val, s.working.value)
 	opt = s
 	return
 }
@@ -157,6 +158,18 @@ func (s *optFlagImpl) ValidArgs(list ...string) (opt OptFlag) {
 	return
 }
 
+func (s *optFlagImpl) FileCompletion(b bool) (opt OptFlag) {
+	s.working.FileCompletion = b
+	opt = s
+	return
+}
+
+func (s *optFlagImpl) Completer(completer Completer) (opt OptFlag) {
+	s.working.Completer = completer
+	opt = s
+	return
+}
+
 func (s *optFlagImpl) HeadLike(enable bool, min, max int64) (opt OptFlag) {
 	s.working.HeadLike = enable
 	s.working.Min, s.working.Max = min, max
@@ -164,6 +177,12 @@ func (s *optFlagImpl) HeadLike(enable bool, min, max int64) (opt OptFlag) {
 	return
 }
 
+func (s *optFlagImpl) Range(min, max int64) (opt OptFlag) {
+	s.working.Min, s.working.Max = min, max
+	opt = s
+	return
+}
+
 func (s *optFlagImpl) EnvKeys(keys ...string) (opt OptFlag) {
 	s.working.EnvVars = uniAddStrs(s.working.EnvVars, keys...)
 	opt = s
@@ -180,6 +199,22 @@ func (s *optFlagImpl) Required(required ...bool) (opt OptFlag) {
 	return
 }
 
+func (s *optFlagImpl) Negatable(negatable ...bool) (opt OptFlag) {
+	var b bool = true
+	for _, bb := range negatable {
+		b = bb
+	}
+	s.working.Negatable = b
+	opt = s
+	return
+}
+
+func (s *optFlagImpl) OptionalValue(noOptDefVal string) (opt OptFlag) {
+	s.working.NoOptDefVal = noOptDefVal
+	opt = s
+	return
+}
+
 func (s *optFlagImpl) OnSet(f func(keyPath string, value interface{})) (opt OptFlag) {
 	s.working.onSet = f
 	opt = s
@@ -4,7 +4,11 @@
 
 package cmdr
 
-import "time"
+import (
+	"net"
+	"net/url"
+	"time"
+)
 
 type (
 	// RootCmdOpt for fluent api
@@ -42,6 +46,16 @@ type (
 		optFlagImpl
 	}
 
+	// stringMapOpt for fluent api
+	stringMapOpt struct {
+		optFlagImpl
+	}
+
+	// intMapOpt for fluent api
+	intMapOpt struct {
+		optFlagImpl
+	}
+
 	// boolOpt for fluent api
 	boolOpt struct {
 		optFlagImpl
@@ -91,6 +105,21 @@ type (
 	durationOpt struct {
 		optFlagImpl
 	}
+
+	// valueOpt for fluent api
+	valueOpt struct {
+		optFlagImpl
+	}
+
+	// sizeOpt for fluent api
+	sizeOpt struct {
+		optFlagImpl
+	}
+
+	// counterOpt for fluent api
+	counterOpt struct {
+		optFlagImpl
+	}
 )
 
 // Header for fluent api
@@ -232,6 +261,47 @@ func NewStringSlice(defaultValue ...string) (opt OptFlag) {
 	return
 }
 
+// NewStringMap creates a wrapped OptFlag, you can connect it to a OptCmd via OptFlag.AttachXXX later.
+//
+// The value is given as "k1=v1,k2=v2" and the repeated occurrences are
+// merged, so `--label a=1 --label b=2` results in map[a:1 b:2]. The
+// entries are stored as the children of the option, so the whole map
+// can be retrieved by cmdr.GetMapR.
+//
+// Sample:
+//
+//    cmdr.NewStringMap(map[string]string{"env": "dev"}).Titles("label", "l").Group("").
+//        AttachTo(parentCmdOpt)
+func NewStringMap(defaultValue ...map[string]string) (opt OptFlag) {
+	workingFlag := &Flag{}
+	opt = &stringMapOpt{optFlagImpl: optFlagImpl{working: workingFlag}}
+	var dv = make(map[string]string)
+	for _, v := range defaultValue {
+		dv = v
+	}
+	opt.DefaultValue(dv, "")
+	return
+}
+
+// NewIntMap creates a wrapped OptFlag, you can connect it to a OptCmd via OptFlag.AttachXXX later.
+//
+// It's a map[string]int flag, see also NewStringMap.
+//
+// Sample:
+//
+//    cmdr.NewIntMap(map[string]int{"cpu": 1}).Titles("limit", "").Group("").
+//        AttachTo(parentCmdOpt)
+func NewIntMap(defaultValue ...map[string]int) (opt OptFlag) {
+	workingFlag := &Flag{}
+	opt = &intMapOpt{optFlagImpl: optFlagImpl{working: workingFlag}}
+	var dv = make(map[string]int)
+	for _, v := range defaultValue {
+		dv = v
+	}
+	opt.DefaultValue(dv, "")
+	return
+}
+
 // NewIntSlice creates a wrapped OptFlag, you can connect it to a OptCmd via OptFlag.AttachXXX later.
 //
 // Sample:
@@ -377,6 +447,127 @@ func NewDuration(defaultValue ...time.Duration) (opt OptFlag) {
 	return
 }
 
+// NewTime creates a wrapped OptFlag, you can connect it to a OptCmd via OptFlag.AttachXXX later.
+//
+// The value is parsed with layout by time.Parse, time.RFC3339 is used
+// if layout is empty. Retrieve it by cmdr.GetTimeR.
+//
+// Sample:
+//
+//    cmdr.NewTime("2006-01-02").Titles("since", "s").Group("").
+//        AttachTo(parentCmdOpt)
+func NewTime(layout string, defaultValue ...time.Time) (opt OptFlag) {
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	workingFlag := &Flag{value: &timeValue{layout: layout}}
+	opt = &valueOpt{optFlagImpl: optFlagImpl{working: workingFlag}}
+	var dv time.Time
+	for _, v := range defaultValue {
+		dv = v
+	}
+	opt.DefaultValue(dv, "")
+	return
+}
+
+// NewIP creates a wrapped OptFlag for an IPv4 or IPv6 address, you can connect it to a OptCmd via OptFlag.AttachXXX later.
+//
+// Retrieve it by cmdr.GetIPR.
+func NewIP(defaultValue ...net.IP) (opt OptFlag) {
+	workingFlag := &Flag{}
+	opt = &valueOpt{optFlagImpl: optFlagImpl{working: workingFlag}}
+	var dv net.IP
+	for _, v := range defaultValue {
+		dv = v
+	}
+	opt.DefaultValue(dv, "")
+	return
+}
+
+// NewIPNet creates a wrapped OptFlag for a CIDR notation such as "192.0.2.0/24", you can connect it to a OptCmd via OptFlag.AttachXXX later.
+//
+// Retrieve it by cmdr.GetIPNetR.
+func NewIPNet(defaultValue ...*net.IPNet) (opt OptFlag) {
+	workingFlag := &Flag{}
+	opt = &valueOpt{optFlagImpl: optFlagImpl{working: workingFlag}}
+	var dv *net.IPNet
+	for _, v := range defaultValue {
+		dv = v
+	}
+	opt.DefaultValue(dv, "")
+	return
+}
+
+// NewURL creates a wrapped OptFlag for an absolute URL, you can connect it to a OptCmd via OptFlag.AttachXXX later.
+//
+// Retrieve it by cmdr.GetURLR.
+func NewURL(defaultValue ...*url.URL) (opt OptFlag) {
+	workingFlag := &Flag{}
+	opt = &valueOpt{optFlagImpl: optFlagImpl{working: workingFlag}}
+	var dv *url.URL
+	for _, v := range defaultValue {
+		dv = v
+	}
+	opt.DefaultValue(dv, "")
+	return
+}
+
+// NewSize creates a wrapped OptFlag for a human readable size, you can connect it to a OptCmd via OptFlag.AttachXXX later.
+//
+// The value takes the SI suffixes (kB, MB, GB, ..., based 1000) and
+// the IEC suffixes (KiB, MiB, GiB, ..., based 1024), such as "1.5GB"
+// and "64MiB". The malformed ones are rejected while parsing. Retrieve
+// it in bytes by cmdr.GetKibibytesR.
+//
+// Sample:
+//
+//    cmdr.NewSize(64 << 20).Titles("buffer-size", "bs").Group("").
+//        AttachTo(parentCmdOpt)
+func NewSize(defaultValue ...uint64) (opt OptFlag) {
+	workingFlag := &Flag{value: &sizeValue{}}
+	opt = &sizeOpt{optFlagImpl: optFlagImpl{working: workingFlag}}
+	var dv uint64
+	for _, v := range defaultValue {
+		dv = v
+	}
+	opt.DefaultValue(dv, "")
+	return
+}
+
+// NewCounter creates a wrapped OptFlag which counts how many times it's given, you can connect it to a OptCmd via OptFlag.AttachXXX later.
+//
+// The count is stored as an int, `-vvv` counts 3, and `--verbose=3`
+// or an environment variable `VERBOSE=3` sets it directly. Retrieve it
+// by cmdr.GetIntR.
+//
+// Sample:
+//
+//    cmdr.NewCounter().Titles("verbose", "v").EnvKeys("VERBOSE").
+//        AttachTo(parentCmdOpt)
+func NewCounter(defaultValue ...int) (opt OptFlag) {
+	workingFlag := &Flag{Counter: true}
+	opt = &counterOpt{optFlagImpl: optFlagImpl{working: workingFlag}}
+	var dv int
+	for _, v := range defaultValue {
+		dv = v
+	}
+	opt.DefaultValue(dv, "")
+	return
+}
+
+// NewValue creates a wrapped OptFlag with a custom Value type, you can connect it to a OptCmd via OptFlag.AttachXXX later.
+//
+// Sample:
+//
+//    cmdr.NewValue(&ipValue{}).Titles("addr", "a").Group("").
+//        AttachTo(parentCmdOpt)
+func NewValue(defaultValue Value) (opt OptFlag) {
+	workingFlag := &Flag{}
+	opt = &valueOpt{optFlagImpl: optFlagImpl{working: workingFlag}}
+	opt.DefaultValue(defaultValue, "")
+	return
+}
+
 // NewDurationFrom creates a wrapped OptFlag, and append it into the current working item.
 func NewDurationFrom(flg *Flag) (opt OptFlag) {
 	optCtx.workingFlag = flg
@@ -13,6 +13,8 @@ import (
 	"gopkg.in/hedzr/errors.v2"
 	"gopkg.in/yaml.v3"
 	"io/ioutil"
+	"net"
+	"net/url"
 	"os"
 	"time"
 )
@@ -464,7 +466,7 @@ func GetSectionFrom(sectionKeyPath string, holder interface{}) (err error) {
 	if fObj != nil {
 		defer handleSerializeError(&err)
 		var b []byte
-		b, err = yaml.Marshal(fObj)
+		b, err = yaml.Marshal(plainValues(fObj))
 		if err == nil {
 			err = yaml.Unmarshal(b, holder)
 			// if err == nil {
@@ -595,20 +597,93 @@ func GetDurationRP(prefix, key string, defaultVal ...time.Duration) time.Duratio
 	return internalGetWorker().rxxtOptions.GetDuration(wrapWithRxxtPrefix(fmt.Sprintf("%s.%s", prefix, key)), defaultVal...)
 }
 
+// GetTime returns the time.Time value of an `Option` key.
+func GetTime(key string, defaultVal ...time.Time) time.Time {
+	return internalGetWorker().rxxtOptions.GetTime(key, defaultVal...)
+}
+
+// GetTimeP returns the time.Time value of an `Option` key.
+func GetTimeP(prefix, key string, defaultVal ...time.Time) time.Time {
+	return internalGetWorker().rxxtOptions.GetTime(fmt.Sprintf("%s.%s", prefix, key), defaultVal...)
+}
+
+// GetTimeR returns the time.Time value of an `Option` key with [WrapWithRxxtPrefix].
+func GetTimeR(key string, defaultVal ...time.Time) time.Time {
+	return internalGetWorker().rxxtOptions.GetTime(wrapWithRxxtPrefix(key), defaultVal...)
+}
+
+// GetTimeRP returns the time.Time value of an `Option` key with [WrapWithRxxtPrefix].
+func GetTimeRP(prefix, key string, defaultVal ...time.Time) time.Time {
+	return internalGetWorker().rxxtOptions.GetTime(wrapWithRxxtPrefix(fmt.Sprintf("%s.%s", prefix, key)), defaultVal...)
+}
+
+// GetIP returns the net.IP value of an `Option` key.
+func GetIP(key string, defaultVal ...net.IP) net.IP {
+	return internalGetWorker().rxxtOptions.GetIP(key, defaultVal...)
+}
+
+// GetIPP returns the net.IP value of an `Option` key.
+func GetIPP(prefix, key string, defaultVal ...net.IP) net.IP {
+	return internalGetWorker().rxxtOptions.GetIP(fmt.Sprintf("%s.%s", prefix, key), defaultVal...)
+}
+
+// GetIPR returns the net.IP value of an `Option` key with [WrapWithRxxtPrefix].
+func GetIPR(key string, defaultVal ...net.IP) net.IP {
+	return internalGetWorker().rxxtOptions.GetIP(wrapWithRxxtPrefix(key), defaultVal...)
+}
+
+// GetIPRP returns the net.IP value of an `Option` key with [WrapWithRxxtPrefix].
+func GetIPRP(prefix, key string, defaultVal ...net.IP) net.IP {
+	return internalGetWorker().rxxtOptions.GetIP(wrapWithRxxtPrefix(fmt.Sprintf("%s.%s", prefix, key)), defaultVal...)
+}
+
+// GetIPNet returns the *net.IPNet value of an `Option` key.
+func GetIPNet(key string, defaultVal ...*net.IPNet) *net.IPNet {
+	return internalGetWorker().rxxtOptions.GetIPNet(key, defaultVal...)
+}
+
+// GetIPNetP returns the *net.IPNet value of an `Option` key.
+func GetIPNetP(prefix, key string, defaultVal ...*net.IPNet) *net.IPNet {
+	return internalGetWorker().rxxtOptions.GetIPNet(fmt.Sprintf("%s.%s", prefix, key), defaultVal...)
+}
+
+// GetIPNetR returns the *net.IPNet value of an `Option` key with [WrapWithRxxtPrefix].
+func GetIPNetR(key string, defaultVal ...*net.IPNet) *net.IPNet {
+	return internalGetWorker().rxxtOptions.GetIPNet(wrapWithRxxtPrefix(key), defaultVal...)
+}
+
+// GetIPNetRP returns the *net.IPNet value of an `Option` key with [WrapWithRxxtPrefix].
+func GetIPNetRP(prefix, key string, defaultVal ...*net.IPNet) *net.IPNet {
+	return internalGetWorker().rxxtOptions.GetIPNet(wrapWithRxxtPrefix(fmt.Sprintf("%s.%s", prefix, key)), defaultVal...)
+}
+
+// GetURL returns the *url.URL value of an `Option` key.
+func GetURL(key string, defaultVal ...*url.URL) *url.URL {
+	return internalGetWorker().rxxtOptions.GetURL(key, defaultVal...)
+}
+
+// GetURLP returns the *url.URL value of an `Option` key.
+func GetURLP(prefix, key string, defaultVal ...*url.URL) *url.URL {
+	return internalGetWorker().rxxtOptions.GetURL(fmt.Sprintf("%s.%s", prefix, key), defaultVal...)
+}
+
+// GetURLR returns the *url.URL value of an `Option` key with [WrapWithRxxtPrefix].
+func GetURLR(key string, defaultVal ...*url.URL) *url.URL {
+	return internalGetWorker().rxxtOptions.GetURL(wrapWithRxxtPrefix(key), defaultVal...)
+}
+
+// GetURLRP returns the *url.URL value of an `Option` key with [WrapWithRxxtPrefix].
+func GetURLRP(prefix, key string, defaultVal ...*url.URL) *url.URL {
+	return internalGetWorker().rxxtOptions.GetURL(wrapWithRxxtPrefix(fmt.Sprintf("%s.%s", prefix, key)), defaultVal...)
+}
+
 // WrapWithRxxtPrefix wrap an key with [RxxtPrefix], for [GetXxx(key)] and [GetXxxP(prefix,key)]
 func WrapWithRxxtPrefix(key string) string {
 	return wrapWithRxxtPrefix(key)
 }
 
 func wrapWithRxxtPrefix(key string) string {
-	if len(internalGetWorker().rxxtPrefixes) == 0 {
-		return key
-	}
-	p := internalGetWorker().getPrefix() // strings.Join(RxxtPrefix, ".")
-	if len(key) == 0 {
-		return p
-	}
-	return p + "." + key
+	return internalGetWorker().wrapWithRxxtPrefix(key)
 }
 
 // Set set the value of an `Option` key (with prefix auto-wrap). The key MUST not have an `app` prefix. eg:
@@ -656,7 +731,7 @@ func AsYaml() (b []byte) {
 
 // AsYamlExt returns a yaml string bytes about all options
 func AsYamlExt() (b []byte, err error) {
-	obj := internalGetWorker().rxxtOptions.GetHierarchyList()
+	obj := plainValues(internalGetWorker().rxxtOptions.GetHierarchyList())
 	defer handleSerializeError(&err)
 	b, err = yaml.Marshal(obj)
 	return
@@ -680,7 +755,7 @@ func AsJSON() (b []byte) {
 
 // AsJSONExt returns a json string bytes about all options
 func AsJSONExt() (b []byte, err error) {
-	obj := internalGetWorker().rxxtOptions.GetHierarchyList()
+	obj := plainValues(internalGetWorker().rxxtOptions.GetHierarchyList())
 	defer handleSerializeError(&err)
 	b, err = json.Marshal(obj)
 	return
@@ -716,7 +791,7 @@ func handleSerializeError(err *error) {
 
 // AsTomlExt returns a toml string bytes about all options
 func AsTomlExt() (b []byte, err error) {
-	obj := internalGetWorker().rxxtOptions.GetHierarchyList()
+	obj := plainValues(internalGetWorker().rxxtOptions.GetHierarchyList())
 	buf := bytes.NewBuffer([]byte{})
 	e := toml.NewEncoder(buf)
 	defer handleSerializeError(&err)
@@ -728,7 +803,7 @@ func AsTomlExt() (b []byte, err error) {
 
 // SaveAsToml to Save all config entries as a toml file
 func SaveAsToml(filename string) (err error) {
-	obj := internalGetWorker().rxxtOptions.GetHierarchyList()
+	obj := plainValues(internalGetWorker().rxxtOptions.GetHierarchyList())
 	err = SaveObjAsToml(obj, filename)
 	return
 }
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"github.com/hedzr/cmdr/tool"
 	"gopkg.in/yaml.v3"
+	"net"
+	"net/url"
 	"os"
 	"reflect"
 	"sort"
@@ -219,6 +221,9 @@ func (s *Options) GetInt64Ex(key string, defaultVal ...int64) (ir int64) {
 //
 // The pure number part can be golang presentation, such as 0x99, 0001b, 0700.
 func (s *Options) GetKibibytesEx(key string, defaultVal ...uint64) (ir64 uint64) {
+	if v, ok := s.Get(key).(*sizeValue); ok {
+		return v.n
+	}
 	sz := s.GetString(key, "")
 	if sz == "" {
 		for _, v := range defaultVal {
@@ -305,6 +310,9 @@ func (s *Options) fromKibibytes(r rune) (times uint64) {
 //
 // The pure number part can be golang presentation, such as 0x99, 0001b, 0700.
 func (s *Options) GetKilobytesEx(key string, defaultVal ...uint64) (ir64 uint64) {
+	if v, ok := s.Get(key).(*sizeValue); ok {
+		return v.n
+	}
 	sz := s.GetString(key, "")
 	if sz == "" {
 		for _, v := range defaultVal {
@@ -638,6 +646,90 @@ func (s *Options) GetDuration(key string, defaultVal ...time.Duration) (ir time.
 	return
 }
 
+// GetTime returns the time.Time value of an `Option` key.
+func (s *Options) GetTime(key string, defaultVal ...time.Time) (ir time.Time) {
+	switch v := nativeOf(s.Get(key)).(type) {
+	case time.Time:
+		if !v.IsZero() {
+			return v
+		}
+	case string:
+		x := &timeValue{layout: time.RFC3339}
+		if w := s.worker(); w != nil && w.rootCommand != nil {
+			// the layout declared on the flag
+			if flg := s.lookupFlag(key, w.rootCommand); flg != nil {
+				if tv, ok := flg.value.(*timeValue); ok {
+					x.layout = tv.layout
+				}
+			}
+		}
+		if x.Set(v) == nil {
+			return x.t
+		}
+	}
+	for _, vv := range defaultVal {
+		ir = vv
+	}
+	return
+}
+
+// GetIP returns the net.IP value of an `Option` key.
+func (s *Options) GetIP(key string, defaultVal ...net.IP) (ir net.IP) {
+	switch v := nativeOf(s.Get(key)).(type) {
+	case net.IP:
+		if v != nil {
+			return v
+		}
+	case string:
+		x := &ipValue{}
+		if x.Set(v) == nil {
+			return x.ip
+		}
+	}
+	for _, vv := range defaultVal {
+		ir = vv
+	}
+	return
+}
+
+// GetIPNet returns the *net.IPNet value of an `Option` key.
+func (s *Options) GetIPNet(key string, defaultVal ...*net.IPNet) (ir *net.IPNet) {
+	switch v := nativeOf(s.Get(key)).(type) {
+	case *net.IPNet:
+		if v != nil {
+			return v
+		}
+	case string:
+		x := &ipNetValue{}
+		if x.Set(v) == nil {
+			return x.n
+		}
+	}
+	for _, vv := range defaultVal {
+		ir = vv
+	}
+	return
+}
+
+// GetURL returns the *url.URL value of an `Option` key.
+func (s *Options) GetURL(key string, defaultVal ...*url.URL) (ir *url.URL) {
+	switch v := nativeOf(s.Get(key)).(type) {
+	case *url.URL:
+		if v != nil {
+			return v
+		}
+	case string:
+		x := &urlValue{}
+		if x.Set(v) == nil {
+			return x.u
+		}
+	}
+	for _, vv := range defaultVal {
+		ir = vv
+	}
+	return
+}
+
 // GetString returns the string value of an `Option` key.
 func (s *Options) GetString(key string, defaultVal ...string) (ret string) {
 	ret = s.GetStringNoExpand(key, defaultVal...)
@@ -681,6 +773,15 @@ func (s *Options) GetStringNoExpand(key string, defaultVal ...string) (ret strin
 	return
 }
 
+// worker returns the ExecWorker which owns this options store, or the
+// default worker if the store was created standalone.
+func (s *Options) worker() *ExecWorker {
+	if s.w != nil {
+		return s.w
+	}
+	return internalGetWorker()
+}
+
 func (s *Options) buildAutomaticEnv(rootCmd *RootCommand) (err error) {
 	// Logger.SetLevel(logrus.DebugLevel)
 
@@ -688,18 +789,29 @@ func (s *Options) buildAutomaticEnv(rootCmd *RootCommand) (err error) {
 	defer s.rwCB.RUnlock()
 
 	// prefix := strings.Join(EnvPrefix,"_")
-	prefix := internalGetWorker().getPrefix() // strings.Join(RxxtPrefix, ".")
+	prefix := s.worker().getPrefix() // strings.Join(RxxtPrefix, ".")
 	for key := range s.entries {
+		flg := s.lookupFlag(key, rootCmd)
+		if flg != nil && flg.isValue() {
+			if err = s.setValueFromConfig(key, flg); err != nil {
+				return
+			}
+		}
+
 		ek := s.envKey(key)
 		if v, ok := os.LookupEnv(ek); ok {
-			if strings.HasPrefix(key, prefix) {
+			if flg != nil && (flg.isMap() || flg.isValue() || flg.Counter) {
+				if err = s.setFromEnv(key, ek, v, flg); err != nil {
+					return
+				}
+			} else if strings.HasPrefix(key, prefix) {
 				s.Set(key[len(prefix)+1:], v)
 			} else {
 				s.Set(key, v)
 			}
 		}
 		// Logger.Printf("buildAutomaticEnv: %v", key)
-		if flg := s.lookupFlag(key, rootCmd); flg != nil {
+		if flg != nil {
 			// flog("    [cmdr] lookupFlag for %q: %v", key, flg.GetTitleName())
 			//
 			// if key == "app.mx-test.test" {
@@ -708,7 +820,12 @@ func (s *Options) buildAutomaticEnv(rootCmd *RootCommand) (err error) {
 			for _, ek := range flg.EnvVars {
 				if v, ok := os.LookupEnv(ek); ok {
 					// flog("    [cmdr][buildAutomaticEnv] envvar %q found (flg=%v): %v", ek, flg.GetTitleName(), v)
-					if strings.HasPrefix(key, prefix) {
+					if flg.isMap() || flg.isValue() || flg.Counter {
+						if err = s.setFromEnv(key, ek, v, flg); err != nil {
+							return
+						}
+						continue
+					} else if strings.HasPrefix(key, prefix) {
 						// Logger.Printf("setnx: %v <-- %v", key, v)
 						s.SetNx(key, v)
 						// Logger.Printf("setnx: %v", s.GetString(key))
@@ -729,16 +846,68 @@ func (s *Options) buildAutomaticEnv(rootCmd *RootCommand) (err error) {
 	// for k, v := range uniqueWorker.envvarToValueMap {
 	// 	_ = os.Setenv(k, v())
 	// }
-	internalGetWorker().setupFromEnvvarMap()
+	w := s.worker()
+	w.setupFromEnvvarMap()
 
-	for _, h := range internalGetWorker().afterAutomaticEnv {
+	for _, h := range w.afterAutomaticEnv {
 		h(rootCmd, s)
 	}
 	return
 }
 
+// setFromEnv sets the map, Value or counter flag with the text of the
+// environment variable ek. A map is replaced, not merged.
+func (s *Options) setFromEnv(key, ek, text string, flg *Flag) (err error) {
+	var v interface{}
+	if flg.isMap() {
+		var m map[string]interface{}
+		if m, err = flg.parseMap(text); err == nil {
+			s.setMap(key, m, false)
+			v = flg.typedMap(s.GetMap(key))
+		}
+	} else if flg.Counter {
+		var n int
+		if n, err = parseCount(text); err == nil {
+			s.SetNx(key, n)
+			v = n
+		}
+	} else {
+		v, err = s.setValue(key, text, flg)
+	}
+	if err != nil {
+		return newError(false, errWrongValue, flg.valueType(), text, flg.GetTitleZshFlagName(), "env var "+ek, err, flg.owner.GetName())
+	}
+	if flg.onSet != nil {
+		flg.onSet(key, nativeOf(v))
+	}
+	return
+}
+
+// setValueFromConfig converts the plain value of a Value flag, which
+// is loaded from the config files, to the Value.
+func (s *Options) setValueFromConfig(key string, flg *Flag) (err error) {
+	val := s.Get(key)
+	if _, ok := val.(Value); ok || val == nil {
+		return
+	}
+	text := valueText(flg.value, val)
+	if _, err = s.setValue(key, text, flg); err != nil {
+		err = newError(false, errWrongValue, flg.valueType(), text, flg.GetTitleZshFlagName(), "config", err, flg.owner.GetName())
+	}
+	return
+}
+
+// setValue sets a copy of the Value of flg with text, and stores it.
+func (s *Options) setValue(key, text string, flg *Flag) (v Value, err error) {
+	v = flg.newValue()
+	if err = v.Set(text); err == nil {
+		s.SetNx(key, v)
+	}
+	return
+}
+
 func (s *Options) lookupFlag(keyPath string, rootCmd *RootCommand) (flg *Flag) {
-	flg = s.loopForLookupFlag(strings.Split(keyPath, ".")[len(internalGetWorker().envPrefixes):], &rootCmd.Command)
+	flg = s.loopForLookupFlag(strings.Split(keyPath, ".")[len(s.worker().envPrefixes):], &rootCmd.Command)
 	return
 }
 
@@ -767,7 +936,7 @@ func (s *Options) loopForLookupFlag(keys []string, cmd *Command) (flg *Flag) {
 func (s *Options) envKey(key string) (envkey string) {
 	key = replaceAll(key, ".", "_")
 	key = replaceAll(key, "-", "_")
-	envkey = strings.Join(append(internalGetWorker().envPrefixes, strings.ToUpper(key)), "_")
+	envkey = strings.Join(append(s.worker().envPrefixes, strings.ToUpper(key)), "_")
 	return
 }
 
@@ -777,7 +946,7 @@ func (s *Options) envKey(key string) (envkey string) {
 // cmdr.GetBool("app.debug") => true
 // ```
 func (s *Options) Set(key string, val interface{}) {
-	k := wrapWithRxxtPrefix(key)
+	k := s.worker().wrapWithRxxtPrefix(key)
 	s.setNx(k, val)
 }
 
@@ -819,6 +988,38 @@ func (s *Options) setNx(key string, val interface{}) (oldval interface{}, modi b
 	return
 }
 
+// setMap stores the entries of m as the children of key, so that the
+// whole map can be retrieved by GetMap(key). The existing children of
+// key are removed at first unless merge is true.
+func (s *Options) setMap(key string, m map[string]interface{}, merge bool) {
+	s.rw.Lock()
+	if node, ok := s.entries[key].(map[string]interface{}); !ok || !merge {
+		for k := range s.entries {
+			if strings.HasPrefix(k, key+".") {
+				delete(s.entries, k)
+			}
+		}
+		a := strings.Split(key, ".")
+		p := s.hierarchy
+		for _, k := range a[:len(a)-1] {
+			child, ok := p[k].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				p[k] = child
+			}
+			p = child
+		}
+		node = make(map[string]interface{})
+		p[a[len(a)-1]] = node
+		s.entries[key] = node
+	}
+	s.rw.Unlock()
+
+	for k, v := range m {
+		s.setNx(key+"."+k, v)
+	}
+}
+
 func isLeaf(oldval, val interface{}) (leaf bool) {
 	if _, ok := oldval.(map[string]interface{}); !ok {
 		if _, ok := val.(map[string]interface{}); !ok {
@@ -966,6 +1167,32 @@ func (s *Options) Reset() {
 	s.entries = make(map[string]interface{})
 }
 
+func (s *Options) markConfigKey(key string) {
+	defer s.rw.Unlock()
+	s.rw.Lock()
+	if s.configKeys == nil {
+		s.configKeys = make(map[string]bool)
+	}
+	s.configKeys[key] = true
+}
+
+// fromConfigFile reports whether the value of key was loaded from the
+// config files.
+func (s *Options) fromConfigFile(key string) bool {
+	defer s.rw.RUnlock()
+	s.rw.RLock()
+	if s.configKeys[key] {
+		return true
+	}
+	// the children of a map, see also Options.setMap
+	for k := range s.configKeys {
+		if strings.HasPrefix(k, key+".") {
+			return true
+		}
+	}
+	return false
+}
+
 func mx(pre, k string) string {
 	if len(pre) == 0 {
 		return k
@@ -1002,6 +1229,7 @@ func (s *Options) loopMap(kdot string, m map[string]interface{}) (err error) {
 		} else {
 			// s.SetNx(mx(kdot, k), v)
 			key := mxIx(kdot, k)
+			s.markConfigKey(key)
 			if oldval, modi := s.setNx(key, v); modi {
 				s.internalRaiseOnMergingSetCB(k, v, oldval)
 			}
@@ -1023,6 +1251,7 @@ func (s *Options) loopIxMap(kdot string, m map[interface{}]interface{}) (err err
 		} else {
 			// s.SetNx(mx(kdot, k), v)
 			key := mxIx(kdot, k)
+			s.markConfigKey(key)
 			if oldval, modi := s.setNx(key, v); modi {
 				s.internalRaiseOnMergingSetCB(key, v, oldval)
 			}
@@ -1051,7 +1280,7 @@ func (s *Options) DumpAsString(showType bool) (str string) {
 	var err error
 	var b []byte
 	defer handleSerializeError(&err)
-	b, err = yaml.Marshal(s.hierarchy)
+	b, err = yaml.Marshal(plainValues(s.hierarchy))
 	if err == nil {
 		if s.GetBoolEx("raw") {
 			str += string(b)
@@ -1070,3 +1299,21 @@ func (s *Options) GetHierarchyList() map[string]interface{} {
 	s.rw.RLock()
 	return s.hierarchy
 }
+
+// plainValues returns a copy of the hierarchy m, in which the Values
+// are replaced with their texts, so that it can be serialized and
+// loaded back.
+func plainValues(m map[string]interface{}) map[string]interface{} {
+	ret := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		switch x := v.(type) {
+		case map[string]interface{}:
+			ret[k] = plainValues(x)
+		case Value:
+			ret[k] = x.String()
+		default:
+			ret[k] = v
+		}
+	}
+	return ret
+}
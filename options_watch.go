@@ -110,10 +110,11 @@ func (s *Options) LoadConfigFile(file string) (err error) {
 	dir := path.Dir(s.usedConfigFile)
 	_ = os.Setenv("CFG_DIR", dir)
 
-	enableWatching := internalGetWorker().watchMainConfigFileToo
+	w := s.worker()
+	enableWatching := w.watchMainConfigFileToo
 	dirWatch := dir
 	filesWatching := []string{}
-	if internalGetWorker().watchMainConfigFileToo {
+	if w.watchMainConfigFileToo {
 		filesWatching = append(filesWatching, s.usedConfigFile)
 	}
 
@@ -129,7 +130,7 @@ func (s *Options) LoadConfigFile(file string) (err error) {
 	if err == nil {
 		err = filepath.Walk(s.usedConfigSubDir, s.visit)
 		if err == nil {
-			if !internalGetWorker().watchMainConfigFileToo {
+			if !w.watchMainConfigFileToo {
 				dirWatch = s.usedConfigSubDir
 			}
 			filesWatching = append(filesWatching, s.configFiles...)
@@ -257,7 +258,8 @@ func (s *Options) reloadConfig() {
 }
 
 func (s *Options) watchConfigDir(configDir string, filesWatching []string) {
-	if internalGetWorker().doNotWatchingConfigFiles || GetBoolR("no-watch-conf-dir") {
+	w := s.worker()
+	if w.doNotWatchingConfigFiles || s.GetBoolEx(w.wrapWithRxxtPrefix("no-watch-conf-dir")) {
 		return
 	}
 
@@ -6,20 +6,19 @@ import (
 	"fmt"
 	"github.com/hedzr/cmdr/conf"
 	"github.com/hedzr/cmdr/tool"
-	"os"
 	"strings"
 )
 
-func (w *ExecWorker) parsePredefinedLocation() (err error) {
+func (w *ExecWorker) parsePredefinedLocation(args []string) (err error) {
 	// pre-detects for `--config xxx`, `--config=xxx`, `--configxxx`
-	if ix, str, yes := partialContains(os.Args, "--config"); yes {
+	if ix, str, yes := partialContains(args, "--config"); yes {
 		var location string
 		if i := strings.Index(str, "="); i > 0 {
 			location = str[i+1:]
 		} else if len(str) > 8 {
 			location = str[8:]
-		} else if ix+1 < len(os.Args) {
-			location = os.Args[ix+1]
+		} else if ix+1 < len(args) {
+			location = args[ix+1]
 		}
 
 		location = tool.StripQuotes(location)
@@ -28,12 +27,12 @@ func (w *ExecWorker) parsePredefinedLocation() (err error) {
 		if len(location) > 0 && FileExists(location) {
 			if yes, err = IsDirectory(location); yes {
 				if FileExists(location + "/conf.d") {
-					setPredefinedLocations(location + "/%s.yml")
+					w.setPredefinedLocations(location + "/%s.yml")
 				} else {
-					setPredefinedLocations(location + "/%s/%s.yml")
+					w.setPredefinedLocations(location + "/%s/%s.yml")
 				}
 			} else if yes, err = IsRegularFile(location); yes {
-				setPredefinedLocations(location)
+				w.setPredefinedLocations(location)
 			}
 		}
 	}
@@ -71,7 +70,7 @@ func (w *ExecWorker) loadFromPredefinedLocation(rootCmd *RootCommand) (err error
 
 // getExpandedPredefinedLocations for internal using
 func (w *ExecWorker) getExpandedPredefinedLocations() (locations []string) {
-	for _, d := range internalGetWorker().predefinedLocations {
+	for _, d := range w.predefinedLocations {
 		locations = uniAddStr(locations, normalizeDir(d))
 	}
 	return
@@ -92,6 +91,6 @@ func GetPredefinedLocations() []string {
 // 	uniqueWorker.predefinedLocations = locations
 // }
 
-func setPredefinedLocations(locations ...string) {
-	internalGetWorker().predefinedLocations = locations
+func (w *ExecWorker) setPredefinedLocations(locations ...string) {
+	w.predefinedLocations = locations
 }
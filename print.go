@@ -16,11 +16,23 @@ import (
 )
 
 func fp(fmtStr string, args ...interface{}) {
-	_, _ = fmt.Fprintf(internalGetWorker().rootCommand.ow, fmtStr+"\n", args...)
+	internalGetWorker().fp(fmtStr, args...)
 }
 
 func ferr(fmtStr string, args ...interface{}) {
-	_, _ = fmt.Fprintf(internalGetWorker().rootCommand.oerr, fmtStr+"\n", args...)
+	internalGetWorker().ferr(fmtStr, args...)
+}
+
+func (w *ExecWorker) fp(fmtStr string, args ...interface{}) {
+	_, _ = fmt.Fprintf(w.rootCommand.ow, fmtStr+"\n", args...)
+}
+
+func (w *ExecWorker) ferr(fmtStr string, args ...interface{}) {
+	_, _ = fmt.Fprintf(w.rootCommand.oerr, fmtStr+"\n", args...)
+}
+
+func (w *ExecWorker) noColorMode() bool {
+	return w.rxxtOptions.GetBoolEx(w.wrapWithRxxtPrefix("no-color"))
 }
 
 func flog(fmtStr string, args ...interface{}) {
@@ -31,10 +43,13 @@ func flog(fmtStr string, args ...interface{}) {
 
 func (w *ExecWorker) printHelp(command *Command, justFlags bool) {
 	initTabStop(defaultTabStop)
+	if command.owner == nil {
+		w.attachPluginCommands(w.rootCommand)
+	}
 
-	if GetIntR("help-zsh") > 0 {
+	if w.rxxtOptions.GetIntEx(w.wrapWithRxxtPrefix("help-zsh")) > 0 {
 		w.printHelpZsh(command, justFlags)
-	} else if GetBoolR("help-bash") {
+	} else if w.rxxtOptions.GetBoolEx(w.wrapWithRxxtPrefix("help-bash")) {
 		// TODO for bash
 		w.printHelpZsh(command, justFlags)
 	} else {
@@ -55,22 +70,22 @@ func (w *ExecWorker) printHelp(command *Command, justFlags bool) {
 
 // paintTildeDebugCommand for `~~debug`
 func (w *ExecWorker) paintTildeDebugCommand(showType bool) {
-	if GetNoColorMode() {
-		fp("\nDUMP:\n\n%v\n", w.rxxtOptions.DumpAsString(showType))
+	if w.noColorMode() {
+		w.fp("\nDUMP:\n\n%v\n", w.rxxtOptions.DumpAsString(showType))
 	} else {
 		// "  [\x1b[2m\x1b[%dm%s\x1b[0m]"
-		fp("\n\x1b[2m\x1b[%dmDUMP:\n\n%v\x1b[0m\n", DarkColor, w.rxxtOptions.DumpAsString(showType))
+		w.fp("\n\x1b[2m\x1b[%dmDUMP:\n\n%v\x1b[0m\n", DarkColor, w.rxxtOptions.DumpAsString(showType))
 
 		if w.rxxtOptions.GetBoolEx("env") {
-			fp("---- ENV: ")
+			w.fp("---- ENV: ")
 			for _, s := range os.Environ() {
 				s2 := strings.Split(s, "=")
-				fp("  - %s = \x1b[2m\x1b[%dm%s\x1b[0m", s2[0], DarkColor, s2[1])
+				w.fp("  - %s = \x1b[2m\x1b[%dm%s\x1b[0m", s2[0], DarkColor, s2[1])
 			}
 		}
 		if w.rxxtOptions.GetBoolEx("more") {
-			fp("---- INFO: ")
-			fp("Exec: \x1b[2m\x1b[%dm%s\x1b[0m, %s", DarkColor, GetExecutablePath(), GetExecutableDir())
+			w.fp("---- INFO: ")
+			w.fp("Exec: \x1b[2m\x1b[%dm%s\x1b[0m, %s", DarkColor, GetExecutablePath(), GetExecutableDir())
 		}
 	}
 }
@@ -111,7 +126,7 @@ func (w *ExecWorker) printHelpZsh(command *Command, justFlags bool) {
 func (w *ExecWorker) printHelpZshCommands(command *Command, justFlags bool) {
 	if !justFlags {
 		var x strings.Builder
-		x.WriteString(fmt.Sprintf("%d: :((", GetIntP(w.getPrefix(), "help-zsh")))
+		x.WriteString(fmt.Sprintf("%d: :((", w.rxxtOptions.GetIntEx(w.wrapWithRxxtPrefix("help-zsh"))))
 		for _, cx := range command.SubCommands {
 			for _, n := range cx.GetExpandableNamesArray() {
 				x.WriteString(fmt.Sprintf(`%v:'%v' `, n, cx.Description))
@@ -123,7 +138,7 @@ func (w *ExecWorker) printHelpZshCommands(command *Command, justFlags bool) {
 			// printHelpZshCommands(cx)
 		}
 		x.WriteString("))")
-		fp("%v", x.String())
+		w.fp("%v", x.String())
 	} else {
 		for _, flg := range command.Flags {
 			// fp(`  %-25s  %v`,
@@ -132,11 +147,11 @@ func (w *ExecWorker) printHelpZshCommands(command *Command, justFlags bool) {
 			// 	flg.GetTitleZshFlagName(), flg.GetDescZsh())
 			for _, ff := range flg.GetTitleZshFlagNamesArray() {
 				// fp(`  %-25s  %v`, ff, flg.GetDescZsh())
-				fp(`%s[%v]`, ff, flg.GetDescZsh())
+				w.fp(`%s[%v]`, ff, flg.GetDescZsh())
 				// fp(`%s[%v]:%v:`, ff, flg.GetDescZsh(), flg.DefaultValuePlaceholder)
 			}
 		}
-		fp(`(: -)--help[Print usage]`)
+		w.fp(`(: -)--help[Print usage]`)
 		// fp(`  %-25s  %v`, "--help", "Print Usage")
 	}
 }
@@ -154,12 +169,12 @@ func (w *ExecWorker) printHelpUsages(p Painter, command *Command) {
 			}
 		}
 
-		cmds := replaceAll(internalGetWorker().backtraceCmdNames(command), ".", " ")
+		cmds := replaceAll(w.backtraceCmdNames(command), ".", " ")
 		if len(cmds) > 0 {
 			cmds += " "
 		}
 
-		p.FpUsagesLine(command, "", w.rootCommand.Name, cmds, ttl, command.TailPlaceHolder)
+		p.FpUsagesLine(command, "", w.rootCommand.Name, cmds, ttl, command.tailPlaceHolder())
 	}
 }
 
@@ -465,7 +480,19 @@ func printHelpFlagSectionsChild(p Painter, command *Command, groups map[string]*
 		flg := groups[nm]
 		if !flg.Hidden {
 			defValStr := ""
-			if flg.DefaultValue != nil {
+			if flg.isMap() {
+				if ss := mapText(flg.DefaultValue); len(ss) > 0 {
+					defValStr = fmt.Sprintf(" (default=%v)", ss)
+				}
+			} else if flg.Counter {
+				if ss := fmt.Sprint(flg.DefaultValue); ss != "0" {
+					defValStr = fmt.Sprintf(" (default=%v)", ss)
+				}
+			} else if flg.value != nil {
+				if ss := flg.value.String(); len(ss) > 0 {
+					defValStr = fmt.Sprintf(" (default %v=%v)", flg.DefaultValuePlaceholder, ss)
+				}
+			} else if flg.DefaultValue != nil {
 				if ss, ok := flg.DefaultValue.(string); ok && len(ss) > 0 {
 					if len(flg.DefaultValuePlaceholder) > 0 {
 						defValStr = fmt.Sprintf(" (default %v='%s')", flg.DefaultValuePlaceholder, ss)
@@ -497,13 +524,16 @@ func printHelpFlagSectionsChild(p Painter, command *Command, groups map[string]*
 func printHelpFlagSections(p Painter, command *Command, justFlags bool) (aGroupedSectionsList []aGroupedSections) {
 	sectionName := "Options"
 
+	// the flags of the parents are listed too, and their lines are
+	// painted for command, such as the notes about the constraints
+	cmd := command
 GoPrintFlags:
-	count := countOfFlagsItems(p, command, justFlags)
+	count := countOfFlagsItems(p, cmd, justFlags)
 	if count > 0 {
 		var gs aGroupedSections
-		k2 := getSortedKeysFromFlgGroupedMap(command.allFlags)
+		k2 := getSortedKeysFromFlgGroupedMap(cmd.allFlags)
 		for _, group := range k2 {
-			groups := command.allFlags[group]
+			groups := cmd.allFlags[group]
 			if len(groups) > 0 {
 				var section = printHelpFlagSectionsChild(p, command, groups, group)
 				if section.maxL > 0 {
@@ -517,13 +547,13 @@ GoPrintFlags:
 		}
 	}
 
-	if command.owner != nil {
-		command = command.owner
+	if cmd.owner != nil {
+		cmd = cmd.owner
 		// sectionName = "Parent/Global Options"
-		if command.owner == nil {
+		if cmd.owner == nil {
 			sectionName = "Global Options"
 		} else {
-			sectionName = fmt.Sprintf("Parent (`%v`) Options", command.GetTitleName())
+			sectionName = fmt.Sprintf("Parent (`%v`) Options", cmd.GetTitleName())
 		}
 		goto GoPrintFlags
 	}
@@ -537,7 +567,7 @@ func (w *ExecWorker) showVersion() {
 		return
 	}
 
-	fp(`v%v
+	w.fp(`v%v
 %v
 %v
 %v
@@ -552,7 +582,7 @@ func (w *ExecWorker) showBuildInfo() {
 
 	w.printHeader(w.currentHelpPainter, &w.rootCommand.Command)
 	// buildTime
-	fp(`
+	w.fp(`
        Built by: %v
 Build Timestamp: %v
         Githash: %v`, conf.GoVersion, conf.Buildstamp, conf.Githash)
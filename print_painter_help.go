@@ -14,15 +14,16 @@ import (
 
 type (
 	helpPainter struct {
+		w *ExecWorker
 	}
 )
 
 func (s *helpPainter) Reset() {
-	internalGetWorker().rootCommand.ow.Flush()
+	s.w.rootCommand.ow.Flush()
 }
 
 func (s *helpPainter) Flush() {
-	internalGetWorker().rootCommand.ow.Flush()
+	s.w.rootCommand.ow.Flush()
 }
 
 func (s *helpPainter) Results() (res []byte) {
@@ -34,11 +35,11 @@ func (s *helpPainter) bufPrintf(sb *bytes.Buffer, fmtStr string, args ...interfa
 }
 
 func (s *helpPainter) Printf(fmtStr string, args ...interface{}) {
-	_, _ = fmt.Fprintf(internalGetWorker().rootCommand.ow, fmtStr+"\n", args...)
+	_, _ = fmt.Fprintf(s.w.rootCommand.ow, fmtStr+"\n", args...)
 }
 
 func (s *helpPainter) Print(fmtStr string, args ...interface{}) {
-	_, _ = fmt.Fprintf(internalGetWorker().rootCommand.ow, fmtStr, args...)
+	_, _ = fmt.Fprintf(s.w.rootCommand.ow, fmtStr, args...)
 }
 
 func (s *helpPainter) FpPrintHeader(command *Command) {
@@ -50,11 +51,11 @@ func (s *helpPainter) FpPrintHeader(command *Command) {
 }
 
 func (s *helpPainter) FpPrintHelpTailLine(command *Command) {
-	if internalGetWorker().enableHelpCommands {
-		if GetNoColorMode() {
-			s.Printf(fmtTailLineNC, internalGetWorker().helpTailLine)
+	if s.w.enableHelpCommands {
+		if s.w.noColorMode() {
+			s.Printf(fmtTailLineNC, s.w.helpTailLine)
 		} else {
-			s.Printf(fmtTailLine, CurrentGroupTitleColor, internalGetWorker().helpTailLine)
+			s.Printf(fmtTailLine, CurrentGroupTitleColor, s.w.helpTailLine)
 		}
 	}
 }
@@ -71,9 +72,7 @@ func (s *helpPainter) FpUsagesLine(command *Command, fmt, appName, cmdList, cmds
 	} else {
 		cmdList = " " + cmdList
 	}
-	if len(tailPlaceHolder) > 0 {
-		tailPlaceHolder = command.TailPlaceHolder
-	} else {
+	if len(tailPlaceHolder) == 0 {
 		tailPlaceHolder = "[tail args...]"
 	}
 	s.Printf("    %s%v%s%s [Options] [Parent/Global Options]"+fmt, appName, cmdList, cmdsTitle, tailPlaceHolder)
@@ -110,7 +109,7 @@ func (s *helpPainter) FpCommandsTitle(command *Command) {
 
 func (s *helpPainter) FpCommandsGroupTitle(group string) {
 	if group != UnsortedGroup {
-		if GetNoColorMode() {
+		if s.w.noColorMode() {
 			s.Printf(fmtCmdGroupTitleNC, tool.StripOrderPrefix(group))
 		} else {
 			s.Printf(fmtCmdGroupTitle, CurrentGroupTitleColor, tool.StripOrderPrefix(group))
@@ -121,7 +120,7 @@ func (s *helpPainter) FpCommandsGroupTitle(group string) {
 func (s *helpPainter) FpCommandsLine(command *Command) (bufL, bufR bytes.Buffer) {
 	if !command.Hidden {
 		if len(command.Deprecated) > 0 {
-			if GetNoColorMode() {
+			if s.w.noColorMode() {
 				s.bufPrintf(&bufL, fmtCmdlineDepNCL, command.GetTitleNames())
 				s.bufPrintf(&bufR, fmtCmdlineDepNCR, command.Description, command.Deprecated)
 			} else {
@@ -129,7 +128,7 @@ func (s *helpPainter) FpCommandsLine(command *Command) (bufL, bufR bytes.Buffer)
 				s.bufPrintf(&bufR, fmtCmdlineDepR, command.Description, command.Deprecated)
 			}
 		} else {
-			if GetNoColorMode() {
+			if s.w.noColorMode() {
 				s.bufPrintf(&bufL, fmtCmdlineNCL, command.GetTitleNames())
 				s.bufPrintf(&bufR, fmtCmdlineNCR, command.Description)
 			} else {
@@ -160,7 +159,7 @@ func (s *helpPainter) FpFlagsTitle(command *Command, flag *Flag, title string) {
 
 func (s *helpPainter) FpFlagsGroupTitle(group string) {
 	if group != UnsortedGroup {
-		if GetNoColorMode() {
+		if s.w.noColorMode() {
 			s.Printf(fmtGroupTitleNC, tool.StripOrderPrefix(group))
 		} else {
 			// fp("  [%s]:", StripOrderPrefix(group))
@@ -176,8 +175,8 @@ func (s *helpPainter) FpFlagsLine(command *Command, flg *Flag, maxShort int, def
 	if len(flg.ValidArgs) > 0 {
 		defValStr = fmt.Sprintf("%v, in %v", defValStr, flg.ValidArgs)
 	}
-	if flg.Min >= 0 && flg.Max > 0 {
-		defValStr = fmt.Sprintf("%v, in [%v..%v]", defValStr, flg.Min, flg.Max)
+	if flg.hasRange() {
+		defValStr = fmt.Sprintf("%v, in %v", defValStr, flg.rangeString())
 	}
 
 	var envKeys string
@@ -193,9 +192,15 @@ func (s *helpPainter) FpFlagsLine(command *Command, flg *Flag, maxShort int, def
 			envKeys = fmt.Sprintf(" [env: %v]", strings.TrimRight(sb.String(), ","))
 		}
 	}
+	if flg.Required {
+		envKeys = " [required]" + envKeys
+	}
+	for _, note := range flagConstraintNotes(command, flg) {
+		envKeys += " [" + note + "]"
+	}
 
 	if len(flg.Deprecated) > 0 {
-		if GetNoColorMode() {
+		if s.w.noColorMode() {
 			s.bufPrintf(&bufL, fmtFlagsDepNCL, // "  %-48s%s%s [deprecated since %v]",
 				flg.GetTitleFlagNamesByMax(",", maxShort))
 			s.bufPrintf(&bufR, fmtFlagsDepNCR, // "  %-48s%s%s [deprecated since %v]",
@@ -207,7 +212,7 @@ func (s *helpPainter) FpFlagsLine(command *Command, flg *Flag, maxShort int, def
 				flg.Description, BgItalic, CurrentDefaultValueColor, envKeys, defValStr, flg.Deprecated)
 		}
 	} else {
-		if GetNoColorMode() {
+		if s.w.noColorMode() {
 			s.bufPrintf(&bufL, fmtFlagsNCL, flg.GetTitleFlagNamesByMax(",", maxShort))
 			s.bufPrintf(&bufR, fmtFlagsNCR, flg.Description, envKeys, defValStr)
 		} else {
@@ -138,9 +138,7 @@ func (s *manPainter) FpUsagesTitle(command *Command, title string) {
 
 func (s *manPainter) FpUsagesLine(command *Command, fmt, appName, cmdList, cmdsTitle, tailPlaceHolder string) {
 	if !command.IsRoot() {
-		if len(tailPlaceHolder) > 0 {
-			tailPlaceHolder = command.TailPlaceHolder
-		} else {
+		if len(tailPlaceHolder) == 0 {
 			tailPlaceHolder = "[tail args...]"
 		}
 		s.Printf(".PP\n\\fB%s\\fP %v%s%s [Options] [Parent/Global Options]"+fmt+"\n\n", appName, cmdList, cmdsTitle, tailPlaceHolder)
@@ -229,6 +227,9 @@ func (s *manPainter) FpFlagsGroupTitle(group string) {
 func (s *manPainter) FpFlagsLine(command *Command, flag *Flag, maxShort int, defValStr string) (bufL, bufR bytes.Buffer) {
 	//s.Printf(".TP\n.BI %s\n%s\n%s\n", manWs(flag.GetTitleFlagNames()), flag.Description, defValStr)
 	s.bufPrintf(&bufL, ".TP\n.BI %s", manWs(flag.GetTitleFlagNames()))
+	if flag.Required {
+		defValStr = "\\fB(required)\\fP" + defValStr
+	}
 	s.bufPrintf(&bufR, "\n%s\n%s\n", flag.Description, defValStr)
 	return
 }
@@ -151,9 +151,7 @@ func (s *markdownPainter) FpUsagesTitle(command *Command, title string) {
 
 func (s *markdownPainter) FpUsagesLine(command *Command, fmt, appName, cmdList, cmdsTitle, tailPlaceHolder string) {
 	if !command.IsRoot() {
-		if len(tailPlaceHolder) > 0 {
-			tailPlaceHolder = command.TailPlaceHolder
-		} else {
+		if len(tailPlaceHolder) == 0 {
 			tailPlaceHolder = "[tail args...]"
 		}
 		s.Printf("```bash\n%s %v%s%s [Options] [Parent/Global Options]"+fmt+"\n```\n",
@@ -286,6 +284,9 @@ func (s *markdownPainter) FpFlagsLine(command *Command, flag *Flag, maxShort int
 	if len(flag.Short) > 0 && len(flag.Full) > 0 {
 		s.bufPrintf(&bufL, " (**Short**: -%v) ", flag.Short)
 	}
+	if flag.Required {
+		s.bufPrintf(&bufL, " (**Required**) ")
+	}
 	if len(flag.Aliases) > 0 {
 		tt := strings.Join(flag.Aliases, ", --")
 		if len(tt) > 0 {
@@ -10,7 +10,8 @@ import (
 )
 
 func dumpTreeForAllCommands(cmd *Command, args []string) (err error) {
-	command := &internalGetWorker().rootCommand.Command
+	w := cmd.worker()
+	command := &w.rootCommand.Command
 	_ = walkFromCommand(command, 0, func(cmd *Command, index int) (e error) {
 		if cmd.Hidden {
 			return
@@ -26,7 +27,7 @@ func dumpTreeForAllCommands(cmd *Command, args []string) (err error) {
 			// 	BgNormal, CurrentDescColor, cmd.Description)
 
 			if len(cmd.Deprecated) > 0 {
-				if GetNoColorMode() {
+				if w.noColorMode() {
 					fmt.Printf("%s%s - %s [deprecated since %v]\n",
 						sp, cmd.GetTitleNames(), cmd.Description, cmd.Deprecated)
 				} else {
@@ -35,7 +36,7 @@ func dumpTreeForAllCommands(cmd *Command, args []string) (err error) {
 						cmd.Deprecated)
 				}
 			} else {
-				if GetNoColorMode() {
+				if w.noColorMode() {
 					fmt.Printf("%s%s - %s\n", sp, cmd.GetTitleNames(), cmd.Description)
 				} else {
 					fmt.Printf("%s%s - \x1b[%dm\x1b[%dm%s\x1b[0m\n",
@@ -0,0 +1,227 @@
+// Copyright © 2020 Hedzr Yeh.
+
+package cmdr
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// promptMissing asks for the missing required flags and positional
+// arguments of goCommand, see WithPromptRequired. The answered
+// arguments are appended to remainArgs.
+//
+// Nothing is asked if the input stream is a file but not a terminal,
+// such as a pipe. And the prompting stops at EOF, so that the missing
+// ones are reported by checkArgs as usual.
+func (w *ExecWorker) promptMissing(goCommand *Command, remainArgs []string) (ret []string, err error) {
+	ret = remainArgs
+	if !w.promptRequired {
+		return
+	}
+	readLine, ok := w.promptInput()
+	if !ok {
+		return
+	}
+
+	for cmd := goCommand; cmd != nil; cmd = cmd.owner {
+		for _, flg := range cmd.Flags {
+			if !flg.Required || w.flagGiven(flg) {
+				continue
+			}
+			if e := w.promptFlag(flg, readLine); e != nil {
+				flog("    [prompt] %v", e)
+				return
+			}
+		}
+	}
+
+	i := 0
+	for _, arg := range goCommand.Args {
+		if i < len(ret) {
+			if i++; arg.Variadic {
+				i = len(ret)
+			}
+			continue
+		}
+		if !arg.Required {
+			break
+		}
+		var texts []string
+		if texts, err = w.promptArg(arg, readLine); err != nil {
+			flog("    [prompt] %v", err)
+			err = nil
+			return
+		}
+		ret = append(ret, texts...)
+		i = len(ret)
+	}
+	return
+}
+
+// promptFlag asks for the value of flg until a valid one is answered.
+func (w *ExecWorker) promptFlag(flg *Flag, readLine func(password bool) (string, error)) (err error) {
+	title := flg.Description
+	if title == "" {
+		title = flg.GetTitleZshFlagName()
+	}
+	password := flg.ExternalTool == ExternalToolPasswordInput
+	def := ""
+	if flg.DefaultValue != nil && !password {
+		def = fmt.Sprint(flg.DefaultValue)
+	}
+
+	for {
+		var text string
+		if text, err = w.prompt(title, def, flg.ValidArgs, password, readLine); err != nil {
+			return
+		}
+		if err = w.setFlagText(flg, text); err == nil {
+			return
+		}
+		w.ferr("%v", err)
+	}
+}
+
+// promptArg asks for the positional argument arg until a valid one is
+// answered. The answer is split in the shell style if arg is variadic.
+func (w *ExecWorker) promptArg(arg *PositionalArg, readLine func(password bool) (string, error)) (texts []string, err error) {
+	title := arg.Description
+	if title == "" {
+		title = "<" + arg.Name + ">"
+	}
+	def := ""
+	if arg.DefaultValue != nil {
+		def = fmt.Sprint(arg.DefaultValue)
+	}
+
+	for {
+		var text string
+		if text, err = w.prompt(title, def, arg.ValidArgs, false, readLine); err != nil {
+			return
+		}
+		texts = []string{text}
+		if arg.Variadic {
+			if texts, _, err = splitResponseFile(text); err != nil {
+				w.ferr("<%v>: %v", arg.Name, err)
+				continue
+			}
+		}
+		for _, s := range texts {
+			if _, err = arg.convert(s); err != nil {
+				w.ferr("<%v>: %v", arg.Name, err)
+				break
+			}
+		}
+		if err == nil && len(texts) > 0 {
+			return
+		}
+	}
+}
+
+// prompt shows title and the numbered choices to the error stream, so
+// that they are not mixed into the output of the command, and reads the
+// answer.
+// An empty answer takes def, and a number takes the choice. It asks
+// again if the answer is empty, or is not one of the choices.
+func (w *ExecWorker) prompt(title, def string, choices []string, password bool, readLine func(password bool) (string, error)) (text string, err error) {
+	for i, c := range choices {
+		_, _ = fmt.Fprintf(w.rootCommand.oerr, "  %d) %v\n", i+1, c)
+	}
+	p := title
+	if def != "" {
+		p += " [" + def + "]"
+	}
+
+	for {
+		_, _ = fmt.Fprintf(w.rootCommand.oerr, "%v: ", p)
+		_ = w.rootCommand.oerr.Flush()
+		if text, err = readLine(password); err != nil {
+			return
+		}
+		if !password {
+			text = strings.TrimSpace(text)
+		}
+		if text == "" {
+			text = def
+		}
+		if text == "" {
+			continue
+		}
+		if len(choices) == 0 {
+			return
+		}
+		if n, e := strconv.Atoi(text); e == nil && n >= 1 && n <= len(choices) {
+			return choices[n-1], nil
+		}
+		for _, c := range choices {
+			if c == text {
+				return
+			}
+		}
+		w.ferr("%q is not one of %v", text, choices)
+	}
+}
+
+// setFlagText sets the value of flg from text, as if it's given in
+// command-line, such as `--port=8080`.
+func (w *ExecWorker) setFlagText(flg *Flag, text string) (err error) {
+	pkg := &ptpkg{w: w, flg: flg, a: "--" + flg.GetTitleName(), fn: flg.GetTitleName(), val: text, assigned: true}
+	if isBool(flg.DefaultValue) {
+		var b bool
+		if b, err = strconv.ParseBool(text); err != nil {
+			return fmt.Errorf("%q is not a valid bool", text)
+		}
+		if pkg.suffix = '-'; b {
+			pkg.suffix = '+'
+		}
+	} else if !flg.isValue() && !flg.isMap() && !flg.Counter {
+		if _, err = convertText(text, flg.DefaultValue); err != nil {
+			return
+		}
+	}
+	if err = pkg.tryExtractingValue(nil); err == nil {
+		flg.times++
+	}
+	return
+}
+
+// promptInput returns the reader of the answers. The input stream is
+// read by lines if it's not a file, such as the scripted answers given
+// by WithInternalInputStream. A file must be a terminal, and then the
+// password is read without echo.
+func (w *ExecWorker) promptInput() (readLine func(password bool) (string, error), ok bool) {
+	f, isFile := w.defaultStdin.(*os.File)
+	if isFile && !isTerminal(f) {
+		return
+	}
+	readLine = func(password bool) (string, error) {
+		if password && isFile {
+			text, err := readPassword(f)
+			_, _ = fmt.Fprintln(w.rootCommand.oerr)
+			_ = w.rootCommand.oerr.Flush()
+			return text, err
+		}
+		return w.stdinLine()
+	}
+	return readLine, true
+}
+
+// stdinLine reads a line from the input stream, without the line
+// ending. It's shared by the shell and the prompts, so that the lines
+// buffered are not lost.
+func (w *ExecWorker) stdinLine() (line string, err error) {
+	if w.stdinReader == nil {
+		w.stdinReader = bufio.NewReader(w.defaultStdin)
+	}
+	line, err = w.stdinReader.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	line = strings.TrimRight(line, "\r\n")
+	return
+}
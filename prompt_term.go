@@ -0,0 +1,23 @@
+// +build !nacl
+
+// Copyright © 2020 Hedzr Yeh.
+
+package cmdr
+
+import (
+	"golang.org/x/crypto/ssh/terminal"
+	"os"
+)
+
+// isTerminal reports whether f is a terminal.
+func isTerminal(f *os.File) bool {
+	return terminal.IsTerminal(int(f.Fd()))
+}
+
+// readPassword reads a line from the terminal f without echo, the new
+// line isn't echoed either.
+func readPassword(f *os.File) (text string, err error) {
+	var b []byte
+	b, err = terminal.ReadPassword(int(f.Fd()))
+	return string(b), err
+}
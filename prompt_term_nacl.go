@@ -0,0 +1,19 @@
+// +build nacl
+
+// Copyright © 2020 Hedzr Yeh.
+
+package cmdr
+
+import (
+	"os"
+)
+
+// isTerminal is always false in nacl, so nothing is prompted.
+func isTerminal(f *os.File) bool {
+	return false
+}
+
+// readPassword is not supported in nacl.
+func readPassword(f *os.File) (text string, err error) {
+	return
+}
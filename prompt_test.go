@@ -0,0 +1,83 @@
+// Copyright © 2020 Hedzr Yeh.
+
+package cmdr_test
+
+import (
+	"fmt"
+	"github.com/hedzr/cmdr"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestPromptRequired(t *testing.T) {
+	c := &cmdrTester{T: t}
+	c.newRoot = func() *cmdr.RootCommand {
+		root := cmdr.Root("prompt", "1.0.0")
+		cmd := root.NewSubCommand("login").
+			PositionalArgs(
+				&cmdr.PositionalArg{Name: "host", Description: "remote host", Required: true},
+				&cmdr.PositionalArg{Name: "files", Required: true, Variadic: true},
+			).
+			Action(c.action)
+		cmdr.NewString("").Titles("user", "u").Description("user name").Required().AttachTo(cmd)
+		cmdr.NewString("").Titles("password", "").Description("password").
+			ExternalTool(cmdr.ExternalToolPasswordInput).Required().AttachTo(cmd)
+		cmdr.NewString("info").Titles("level", "").Description("log level").
+			ValidArgs("debug", "info", "warn").Required().AttachTo(cmd)
+		cmdr.NewInt(8080).Titles("port", "p").Required().AttachTo(cmd)
+		return root.RootCommand()
+	}
+	c.result = func(w *cmdr.ExecWorker) string {
+		if c.cmd == nil {
+			return none
+		}
+		opts, key := optionsOf(w, "login")
+		return fmt.Sprintf("user=%q password=%q level=%q port=%v %q", opts.GetString(key("user")),
+			opts.GetString(key("password")), opts.GetString(key("level")), opts.GetIntEx(key("port")), c.args)
+	}
+
+	f, err := ioutil.TempFile("", "cmdr-prompt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	_, _ = f.WriteString("hedzr\n")
+	_, _ = f.Seek(0, 0)
+
+	for _, tc := range []struct {
+		prompt bool
+		in     io.Reader
+		args   string
+		want   string // the expected values, or the expected error message
+	}{
+		{true, strings.NewReader("\nhedzr\n s3cret \n5\n1\nabc\n\nh1\na 'b c'"), "login",
+			`user="hedzr" password=" s3cret " level="debug" port=8080 ["h1" "a" "b c"]`},
+		{true, strings.NewReader("p\nwarn\n9000\nf"), "login -u me h1",
+			`user="me" password="p" level="warn" port=9000 ["h1" "f"]`},
+		{true, strings.NewReader(""), "login -u me --password p --level info -p 1 h1 f", `user="me" password="p" level="info" port=1 ["h1" "f"]`},
+		{true, strings.NewReader("hedzr\n"), "login", "required flags missed: --password, --level, --port, under command 'login'"},
+		{true, strings.NewReader("hedzr\np\n\n\nh1\n"), "login", "invalid arguments: missing <files>, under command 'login'"},
+		{true, f, "login", "required flags missed: --user, --password, --level, --port, under command 'login'"},
+		{false, strings.NewReader("hedzr\n"), "login", "required flags missed: --user, --password, --level, --port, under command 'login'"},
+	} {
+		c.opts = []cmdr.ExecOption{cmdr.WithPromptRequired(tc.prompt), cmdr.WithInternalInputStream(tc.in)}
+		c.expect(conformance{{tc.args, tc.want}})
+	}
+
+	// the prompts are written to the error stream, but not the output
+	_, stdout, stderr, _ := runWorker(c.newRoot(), []string{"prompt", "login"},
+		cmdr.WithPromptRequired(true), cmdr.WithInternalInputStream(strings.NewReader("\nhedzr\np\nx\n3\n\nh1\nf\n")))
+	for _, s := range []string{"user name: user name: password: ", "  1) debug\n  2) info\n  3) warn\nlog level [info]: ",
+		"log level [info]: \"x\" is not one of [debug info warn]\nlog level [info]: ", "--port [8080]: ", "remote host: ", "<files>: "} {
+		if !strings.Contains(stderr, s) {
+			t.Errorf("expect %q in the prompts:\n%v", s, stderr)
+		}
+	}
+	if stdout != "" {
+		t.Errorf("expect no prompts in the output:\n%v", stdout)
+	}
+}
@@ -16,10 +16,12 @@ import (
 )
 
 type ptpkg struct {
+	w                 *ExecWorker
 	assigned          bool
 	found             bool
 	short             bool
 	lastCommandHeld   bool
+	optionsEnded      bool
 	fn, val           string
 	savedFn, savedVal string
 	i                 int
@@ -55,16 +57,16 @@ func (pkg *ptpkg) Reset() {
 func (pkg *ptpkg) tryToggleGroup() {
 	tg := pkg.flg.ToggleGroup
 	if len(tg) > 0 {
-		wkr := internalGetWorker()
+		wkr := pkg.w
 		for _, f := range pkg.flg.owner.Flags {
 			if f.ToggleGroup == tg && (isBool(f.DefaultValue) || isNil1(f.DefaultValue)) {
+				// the choice is kept in the options only, the flags are
+				// shared by the runs
 				if f != pkg.flg {
 					wkr.rxxtOptions.Set(wkr.backtraceFlagNames(f), false)
-					f.DefaultValue = false
 				} else {
 					wkr.rxxtOptions.Set(wkr.backtraceFlagNames(f), true)
 					wkr.rxxtOptions.Set(wkr.backtraceCmdNames(f.owner)+"."+f.ToggleGroup, f.Full)
-					f.DefaultValue = true
 				}
 			}
 		}
@@ -103,7 +105,11 @@ func (pkg *ptpkg) matchShortFlag(goCommand *Command, a string) (i int) {
 	}
 	matched := []MS{}
 	longest := -1
-	for i = len(a); i > 1; i-- {
+	start := len(a)
+	if pkg.w.parsingMode.conforming() && start > 2 {
+		start = 2 // the single-char short flags only
+	}
+	for i = start; i > 1; i-- {
 		fn := a[1:i]
 		if _, ok := goCommand.plainShortFlags[fn]; ok {
 			matched = append(matched, struct {
@@ -127,9 +133,15 @@ func (pkg *ptpkg) matchShortFlag(goCommand *Command, a string) (i int) {
 }
 
 func (pkg *ptpkg) tryExtractingValue(args []string) (err error) {
+	if pkg.flg.isValue() {
+		return pkg.processTypeValue(args)
+	}
 	if _, ok := pkg.flg.DefaultValue.(bool); ok {
 		return pkg.tryExtractingBoolValue()
 	}
+	if pkg.flg.Counter {
+		return pkg.processTypeCounter()
+	}
 
 	vv := reflect.ValueOf(pkg.flg.DefaultValue)
 	kind := vv.Kind()
@@ -140,6 +152,9 @@ func (pkg *ptpkg) tryExtractingValue(args []string) (err error) {
 	case reflect.Slice:
 		err = pkg.tryExtractingSliceValue(args)
 
+	case reflect.Map:
+		err = pkg.processTypeMap(args)
+
 	default:
 		err = pkg.tryExtractingOthers(args, kind)
 	}
@@ -152,7 +167,7 @@ func (pkg *ptpkg) tryExtractingOthers(args []string, kind reflect.Kind) (err err
 	if isTypeSInt(kind) {
 		if _, ok := pkg.flg.DefaultValue.(time.Duration); ok {
 			if err = pkg.processTypeDuration(args); err != nil {
-				ferr("wrong time.Duration: flag=%v, value=%v", pkg.fn, pkg.val)
+				pkg.w.ferr("wrong time.Duration: flag=%v, value=%v", pkg.fn, pkg.val)
 				return
 			}
 			// ferr("wrong time.Duration: flag=%v, value=%v", pkg.fn, pkg.val)
@@ -166,7 +181,7 @@ func (pkg *ptpkg) tryExtractingOthers(args []string, kind reflect.Kind) (err err
 	} else if isTypeComplex(kind) {
 		err = pkg.processTypeComplex(args)
 	} else {
-		ferr("Unacceptable default value kind=%v", kind)
+		pkg.w.ferr("Unacceptable default value kind=%v", kind)
 	}
 	return
 }
@@ -186,21 +201,81 @@ func (pkg *ptpkg) tryExtractingSliceValue(args []string) (err error) {
 func (pkg *ptpkg) tryExtractingBoolValue() (err error) {
 	// bool flag, -D+, -D-
 
+	var v = true
 	if pkg.suffix == '+' {
-		pkg.flg.DefaultValue = true
+		v = true
 	} else if pkg.suffix == '-' {
-		pkg.flg.DefaultValue = false
-	} else {
-		pkg.flg.DefaultValue = true
+		v = false
+	} else if pkg.negated() {
+		v = false
+		pkg.setNegatedPeers()
 	}
 
-	var v = pkg.flg.DefaultValue
-	var keyPath = internalGetWorker().backtraceFlagNames(pkg.flg)
+	var keyPath = pkg.w.backtraceFlagNames(pkg.flg)
 	pkg.xxSet(keyPath, v)
 	return
 }
 
+// negated reports whether the matched long name is a negated form of
+// the Negatable flag, such as `--no-color`.
+func (pkg *ptpkg) negated() bool {
+	return !pkg.short && pkg.flg.isNegated(pkg.fn)
+}
+
+// setNegatedPeers sets the bool flags which are named as the negated
+// form, such as the builtin `--no-color`, to true, so that both of them
+// work while a Negatable flag `--color` is defined.
+func (pkg *ptpkg) setNegatedPeers() {
+	for c := pkg.flg.owner; c != nil; c = c.owner {
+		for _, f := range c.Flags {
+			if f != pkg.flg && f.Full == pkg.fn && isBool(f.DefaultValue) {
+				keyPath := pkg.w.backtraceFlagNames(f)
+				pkg.w.rxxtOptions.Set(keyPath, true)
+				if f.onSet != nil {
+					f.onSet(keyPath, true)
+				}
+				f.times++
+			}
+		}
+	}
+}
+
+// processTypeCounter increases the count for each matching, such as
+// -vvv, or sets it with an attached value, such as --verbose=3. Unlike
+// the other flags, the next argument is never taken as the value.
+func (pkg *ptpkg) processTypeCounter() (err error) {
+	var wkr = pkg.w
+	var keyPath = wkr.backtraceFlagNames(pkg.flg)
+	var key = keyPath
+	if pkg.a[0] != '~' {
+		key = wkr.wrapWithRxxtPrefix(keyPath)
+	}
+
+	n := 1
+	if pkg.assigned {
+		if n, err = parseCount(pkg.val); err != nil {
+			pkg.found = true
+			err = newError(false, errWrongValue,
+				"count", pkg.val, pkg.flg.GetTitleZshFlagName(), "command-line", err, pkg.flg.owner.GetName(),
+			)
+			return
+		}
+	} else if pkg.flg.times > 1 {
+		n = wkr.rxxtOptions.GetIntEx(key) + 1
+	}
+	if err = pkg.checkRange(n); err != nil {
+		return
+	}
+	pkg.xxSet(keyPath, n)
+	return
+}
+
 func (pkg *ptpkg) preprocessPkg(args []string) (err error) {
+	if !pkg.assigned && len(pkg.flg.NoOptDefVal) > 0 {
+		// the optional value must be attached, such as --color=always
+		pkg.val = pkg.flg.NoOptDefVal
+		return
+	}
 	if !pkg.assigned {
 		if len(pkg.savedVal) > 0 {
 			pkg.val = pkg.savedVal
@@ -211,7 +286,10 @@ func (pkg *ptpkg) preprocessPkg(args []string) (err error) {
 		} else {
 			yes := false
 			if pkg.i < len(args)-1 {
-				if len(args[pkg.i+1]) == 0 {
+				if pkg.w.parsingMode.conforming() {
+					// the next arg is the value, even if it starts with '-'
+					yes = true
+				} else if len(args[pkg.i+1]) == 0 {
 					yes = true
 				} else if args[pkg.i+1][0] != '-' && (args[pkg.i+1][0] != '~' || args[pkg.i+1][1] != '~') {
 					yes = true
@@ -223,7 +301,11 @@ func (pkg *ptpkg) preprocessPkg(args []string) (err error) {
 			} else {
 				if len(pkg.flg.ExternalTool) > 0 {
 					err = pkg.processExternalTool()
-				} else if GetStrictMode() {
+				} else if pkg.w.parsingMode.conforming() {
+					pkg.found = true
+					err = newError(false, errFlagValueMissed, pkg.flg.GetTitleZshFlagName(), pkg.flg.owner.GetName())
+					return
+				} else if pkg.w.rxxtOptions.GetBoolEx(pkg.w.wrapWithRxxtPrefix("strict-mode")) {
 					err = errors.New("unexpected end of command line [i=%v,args=(%v)], need more args for %v", pkg.i, args, pkg)
 					return
 				}
@@ -266,12 +348,12 @@ func (pkg *ptpkg) processExternalTool() (err error) {
 
 func (pkg *ptpkg) xxSet(keyPath string, v interface{}) {
 	if pkg.a[0] == '~' {
-		internalGetWorker().rxxtOptions.SetNx(keyPath, v)
+		pkg.w.rxxtOptions.SetNx(keyPath, v)
 	} else {
-		internalGetWorker().rxxtOptions.Set(keyPath, v)
+		pkg.w.rxxtOptions.Set(keyPath, v)
 	}
 	if pkg.flg != nil && pkg.flg.onSet != nil {
-		pkg.flg.onSet(keyPath, v)
+		pkg.flg.onSet(keyPath, nativeOf(v))
 	}
 	pkg.found = true
 }
@@ -288,8 +370,11 @@ func (pkg *ptpkg) processTypeDuration(args []string) (err error) {
 		var v time.Duration
 		v, err = time.ParseDuration(pkg.val)
 		if err == nil {
+			if err = pkg.checkRange(v); err != nil {
+				return
+			}
 			// flog("    .  . [duration] %q => %v", pkg.val, v)
-			var keyPath = internalGetWorker().backtraceFlagNames(pkg.flg)
+			var keyPath = pkg.w.backtraceFlagNames(pkg.flg)
 			pkg.xxSet(keyPath, v)
 		}
 	}
@@ -304,11 +389,13 @@ func (pkg *ptpkg) processTypeIntCore(args []string) (err error) {
 			err = pkg.processTypeDuration(args)
 			return
 		}
-		ferr("wrong number (int): flag=%v, number=%v, err: %v", pkg.fn, pkg.val, err)
+		pkg.w.ferr("wrong number (int): flag=%v, number=%v, err: %v", pkg.fn, pkg.val, err)
 		err = errors.New("wrong number (int): flag=%v, number=%v, inner error is: %v", pkg.fn, pkg.val, err)
+	} else if err = pkg.checkRange(v); err != nil {
+		return
 	}
 
-	var keyPath = internalGetWorker().backtraceFlagNames(pkg.flg)
+	var keyPath = pkg.w.backtraceFlagNames(pkg.flg)
 	pkg.xxSet(keyPath, v)
 	return
 }
@@ -318,12 +405,15 @@ func (pkg *ptpkg) processTypeUint(args []string) (err error) {
 		var v uint64
 		v, err = strconv.ParseUint(pkg.val, 0, 0)
 		if err != nil {
-			ferr("wrong number (uint): flag=%v, number=%v, err: %v", pkg.fn, pkg.val, err)
+			pkg.w.ferr("wrong number (uint): flag=%v, number=%v, err: %v", pkg.fn, pkg.val, err)
 			err = errors.New("wrong number (uint): flag=%v, number=%v, inner error is: %v", pkg.fn, pkg.val, err)
 			return
 		}
+		if err = pkg.checkRange(v); err != nil {
+			return
+		}
 
-		var keyPath = internalGetWorker().backtraceFlagNames(pkg.flg)
+		var keyPath = pkg.w.backtraceFlagNames(pkg.flg)
 		pkg.xxSet(keyPath, v)
 	}
 	return
@@ -334,28 +424,43 @@ func (pkg *ptpkg) processTypeFloat(args []string) (err error) {
 		var v float64
 		v, err = strconv.ParseFloat(pkg.val, 0)
 		if err != nil {
-			ferr("wrong number (float): flag=%v, number=%v, err: %v", pkg.fn, pkg.val, err)
+			pkg.w.ferr("wrong number (float): flag=%v, number=%v, err: %v", pkg.fn, pkg.val, err)
 			err = errors.New("wrong number (float): flag=%v, number=%v, inner error is: %v", pkg.fn, pkg.val, err)
 			return
 		}
+		if err = pkg.checkRange(v); err != nil {
+			return
+		}
 
-		var keyPath = internalGetWorker().backtraceFlagNames(pkg.flg)
+		var keyPath = pkg.w.backtraceFlagNames(pkg.flg)
 		pkg.xxSet(keyPath, v)
 	}
 	return
 }
 
+// checkRange validates the numeric value v with the range [Min..Max]
+// of the flag.
+func (pkg *ptpkg) checkRange(v interface{}) (err error) {
+	if !pkg.flg.inRange(v) {
+		pkg.found = true
+		err = newError(false, errValueOutOfRange,
+			v, pkg.flg.GetTitleZshFlagName(), pkg.flg.rangeString(), "command-line", pkg.flg.owner.GetName(),
+		)
+	}
+	return
+}
+
 func (pkg *ptpkg) processTypeComplex(args []string) (err error) {
 	if err = pkg.preprocessPkg(args); err == nil {
 		var v complex128
 		v, err = tool.ParseComplexX(pkg.val)
 		if err != nil {
-			ferr("wrong number (complex): flag=%v, number=%v, err: %v", pkg.fn, pkg.val, err)
+			pkg.w.ferr("wrong number (complex): flag=%v, number=%v, err: %v", pkg.fn, pkg.val, err)
 			err = errors.New("wrong number (complex): flag=%v, number=%v, inner error is: %v", pkg.fn, pkg.val, err)
 			return
 		}
 
-		var keyPath = internalGetWorker().backtraceFlagNames(pkg.flg)
+		var keyPath = pkg.w.backtraceFlagNames(pkg.flg)
 		pkg.xxSet(keyPath, v)
 	}
 	return
@@ -363,7 +468,7 @@ func (pkg *ptpkg) processTypeComplex(args []string) (err error) {
 
 func (pkg *ptpkg) processTypeString(args []string) (err error) {
 	if err = pkg.preprocessPkg(args); err == nil {
-		var wkr = internalGetWorker()
+		var wkr = pkg.w
 
 		if len(pkg.flg.ValidArgs) > 0 {
 			// validate for enum
@@ -394,9 +499,9 @@ func (pkg *ptpkg) processTypeStringSlice(args []string) (err error) {
 	if err = pkg.preprocessPkg(args); err == nil {
 		var v = strings.Split(pkg.val, ",")
 
-		var wkr = internalGetWorker()
+		var wkr = pkg.w
 		var keyPath = wkr.backtraceFlagNames(pkg.flg)
-		var existedVal = wkr.rxxtOptions.GetStringSlice(wrapWithRxxtPrefix(keyPath))
+		var existedVal = wkr.rxxtOptions.GetStringSlice(pkg.w.wrapWithRxxtPrefix(keyPath))
 		if reflect.DeepEqual(existedVal, pkg.flg.DefaultValue) || pkg.flg.times == 1 { // if first matching
 			existedVal = nil
 		}
@@ -414,10 +519,10 @@ func (pkg *ptpkg) processTypeIntSlice(args []string) (err error) {
 			}
 		}
 
-		var wkr = internalGetWorker()
+		var wkr = pkg.w
 		var keyPath = wkr.backtraceFlagNames(pkg.flg)
 		// pkg.xxSet(keyPath, v)
-		var existedVal = wkr.rxxtOptions.GetInt64Slice(wrapWithRxxtPrefix(keyPath))
+		var existedVal = wkr.rxxtOptions.GetInt64Slice(pkg.w.wrapWithRxxtPrefix(keyPath))
 		if reflect.DeepEqual(existedVal, pkg.flg.DefaultValue) || pkg.flg.times == 1 { // if first matching
 			existedVal = nil
 		}
@@ -435,10 +540,10 @@ func (pkg *ptpkg) processTypeUintSlice(args []string) (err error) {
 			}
 		}
 
-		var wkr = internalGetWorker()
+		var wkr = pkg.w
 		var keyPath = wkr.backtraceFlagNames(pkg.flg)
 		// pkg.xxSet(keyPath, v)
-		var existedVal = wkr.rxxtOptions.GetUint64Slice(wrapWithRxxtPrefix(keyPath))
+		var existedVal = wkr.rxxtOptions.GetUint64Slice(pkg.w.wrapWithRxxtPrefix(keyPath))
 		if reflect.DeepEqual(existedVal, pkg.flg.DefaultValue) || pkg.flg.times == 1 { // if first matching
 			existedVal = nil
 		}
@@ -446,3 +551,62 @@ func (pkg *ptpkg) processTypeUintSlice(args []string) (err error) {
 	}
 	return
 }
+
+// processTypeMap parses "k1=v1,k2=v2" and merges the entries into the
+// map option, the first matching replaces the default value.
+func (pkg *ptpkg) processTypeMap(args []string) (err error) {
+	if err = pkg.preprocessPkg(args); err == nil {
+		var m map[string]interface{}
+		if m, err = pkg.flg.parseMap(pkg.val); err != nil {
+			pkg.found = true
+			err = newError(false, errWrongValue,
+				"map", pkg.val, pkg.flg.GetTitleZshFlagName(), "command-line", err, pkg.flg.owner.GetName(),
+			)
+			return
+		}
+
+		var wkr = pkg.w
+		var keyPath = wkr.backtraceFlagNames(pkg.flg)
+		var key = keyPath
+		if pkg.a[0] != '~' {
+			key = wkr.wrapWithRxxtPrefix(keyPath)
+		}
+		wkr.rxxtOptions.setMap(key, m, pkg.flg.times > 1)
+		if pkg.flg.onSet != nil {
+			pkg.flg.onSet(keyPath, pkg.flg.typedMap(wkr.rxxtOptions.GetMap(key)))
+		}
+		pkg.found = true
+	}
+	return
+}
+
+// processTypeValue sets the Value of the flag, the first matching sets
+// a copy of the DefaultValue, and the following ones set the same copy.
+func (pkg *ptpkg) processTypeValue(args []string) (err error) {
+	if err = pkg.preprocessPkg(args); err == nil {
+		var wkr = pkg.w
+		var keyPath = wkr.backtraceFlagNames(pkg.flg)
+		var key = keyPath
+		if pkg.a[0] != '~' {
+			key = wkr.wrapWithRxxtPrefix(keyPath)
+		}
+
+		// the Values are not compared, they might be uncomparable
+		var v Value
+		if pkg.flg.times > 1 {
+			v, _ = wkr.rxxtOptions.Get(key).(Value)
+		}
+		if v == nil {
+			v = pkg.flg.newValue()
+		}
+		if err = v.Set(pkg.val); err != nil {
+			pkg.found = true
+			err = newError(false, errWrongValue,
+				v.Type(), pkg.val, pkg.flg.GetTitleZshFlagName(), "command-line", err, pkg.flg.owner.GetName(),
+			)
+			return
+		}
+		pkg.xxSet(keyPath, v)
+	}
+	return
+}
@@ -0,0 +1,180 @@
+// Copyright © 2020 Hedzr Yeh.
+
+package cmdr
+
+import (
+	"fmt"
+	"gopkg.in/hedzr/errors.v2"
+	"strings"
+)
+
+// shellCommandName is the name of the builtin command which runs the
+// command-lines interactively, see also WithShellCommand.
+const shellCommandName = "shell"
+
+// runState is the per-run state of the flags, which are modified while
+// parsing a command-line, such as Flag.times and the option values.
+// It's restored before running each command-line in the shell.
+type runState struct {
+	times  map[*Flag]int
+	values map[string]interface{}
+	maps   map[string]map[string]interface{}
+}
+
+func (w *ExecWorker) attachShellCommand(root *RootCommand) {
+	if !w.enableShellCommand {
+		return
+	}
+	if _, ok := root.allCmds[SysMgmtGroup][shellCommandName]; ok {
+		return
+	}
+	cx := &Command{
+		BaseOpt: BaseOpt{
+			Full:        shellCommandName,
+			Description: "run the commands interactively, type 'exit' or Ctrl-D to quit",
+			Group:       SysMgmtGroup,
+			owner:       &root.Command,
+			Action: func(cmd *Command, args []string) (err error) {
+				w := cmd.worker()
+				return w.runShell(w.rootCommand)
+			},
+		},
+	}
+	root.SubCommands = uniAddCmd(root.SubCommands, cx)
+	root.allCmds[SysMgmtGroup][shellCommandName] = cx
+	root.plainCmds[shellCommandName] = cx
+}
+
+// runShell reads the command-lines from the input stream, and runs each
+// of them against root, until 'exit', 'quit' or EOF. A command-line is
+// split in the shell style, see splitResponseFile.
+//
+// The xref and the config files are built once, the Options are kept
+// between the command-lines, but the flags are reset to the values
+// before the shell started.
+//
+// On a terminal, the line editing, the history and the tab completion
+// are supported.
+func (w *ExecWorker) runShell(root *RootCommand) (err error) {
+	if w.inShell {
+		return fmt.Errorf("already in the shell")
+	}
+	st := w.saveRunState(root)
+	w.inShell = true
+	defer func() { w.inShell = false }()
+
+	readLine, ok := w.shellTerminal(w.defaultStdin, root.AppName+"> ", func(line string, pos int) (string, int, []string) {
+		return w.shellComplete(root, line, pos)
+	})
+	if !ok {
+		readLine = w.stdinLine
+	}
+
+	for {
+		_ = w.defaultStdout.Flush()
+		_ = w.defaultStderr.Flush()
+		line, e := readLine()
+		if e != nil {
+			return
+		}
+		words, _, e := splitResponseFile(line)
+		if e != nil {
+			w.ferr("%v", e)
+			continue
+		}
+		if len(words) == 0 {
+			continue
+		}
+		if words[0] == "exit" || words[0] == "quit" {
+			return
+		}
+
+		w.restoreRunState(st)
+		flog("--> shell: %q", words)
+		if _, e = w.InternalExecFor(root, append([]string{root.AppName}, words...)); e != nil && e != ErrShouldBeStopException {
+			var ce *ErrorForCmdr
+			if !errors.As(e, &ce) || !ce.printed {
+				w.ferr("%v", e)
+			}
+		}
+	}
+}
+
+// shellComplete completes the word at pos of line, see also
+// completionCandidates. It returns the candidates if the word can't be
+// completed any more.
+func (w *ExecWorker) shellComplete(root *RootCommand, line string, pos int) (newLine string, newPos int, candidates []string) {
+	head := line[:pos]
+	words := strings.Fields(head)
+	if len(words) == 0 || strings.HasSuffix(head, " ") {
+		words = append(words, "")
+	}
+	partial := words[len(words)-1]
+
+	for _, c := range w.completionCandidates(root, words) {
+		candidates = append(candidates, strings.SplitN(c, "\t", 2)[0])
+	}
+	if len(candidates) == 0 {
+		return line, pos, nil
+	}
+
+	common := candidates[0]
+	for _, c := range candidates[1:] {
+		for !strings.HasPrefix(c, common) {
+			common = common[:len(common)-1]
+		}
+	}
+	if len(candidates) == 1 && !strings.HasSuffix(common, "=") {
+		common += " "
+	}
+	if len(common) <= len(partial) || !strings.HasPrefix(common, partial) {
+		return line, pos, candidates
+	}
+	head = head[:len(head)-len(partial)] + common
+	return head + line[pos:], len(head), nil
+}
+
+// saveRunState returns the state of the flags of root and of its
+// sub-commands.
+func (w *ExecWorker) saveRunState(root *RootCommand) (st *runState) {
+	st = &runState{
+		times:  make(map[*Flag]int),
+		values: make(map[string]interface{}),
+		maps:   make(map[string]map[string]interface{}),
+	}
+	s := w.rxxtOptions
+	_ = walkFromCommand(&root.Command, 0, func(cmd *Command, index int) (err error) {
+		for _, flg := range cmd.Flags {
+			st.times[flg] = flg.times
+			key := w.wrapWithRxxtPrefix(w.backtraceFlagNames(flg))
+			if flg.isMap() {
+				m := make(map[string]interface{})
+				for k, v := range s.GetMap(key) {
+					m[k] = v
+				}
+				st.maps[key] = m
+			} else {
+				st.values[key] = s.Get(key)
+			}
+			if flg.ToggleGroup != "" {
+				key = w.wrapWithRxxtPrefix(w.backtraceCmdNames(cmd) + "." + flg.ToggleGroup)
+				st.values[key] = s.Get(key)
+			}
+		}
+		return
+	})
+	return
+}
+
+// restoreRunState restores the state of the flags saved by saveRunState.
+func (w *ExecWorker) restoreRunState(st *runState) {
+	for flg, n := range st.times {
+		flg.times = n
+	}
+	for key, v := range st.values {
+		w.rxxtOptions.SetNx(key, v)
+	}
+	for key, m := range st.maps {
+		w.rxxtOptions.setMap(key, m, false)
+	}
+}
@@ -0,0 +1,52 @@
+// +build !nacl
+
+// Copyright © 2020 Hedzr Yeh.
+
+package cmdr
+
+import (
+	"golang.org/x/crypto/ssh/terminal"
+	"io"
+	"os"
+	"strings"
+)
+
+// shellTerminal returns a line reader with the line editing, the
+// history and the tab completion, if in is a terminal.
+func (w *ExecWorker) shellTerminal(in io.Reader, prompt string, complete func(line string, pos int) (string, int, []string)) (readLine func() (string, error), ok bool) {
+	f, ok := in.(*os.File)
+	if !ok || !isTerminal(f) {
+		return nil, false
+	}
+
+	fd := int(f.Fd())
+	t := terminal.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{f, os.Stdout}, prompt)
+	t.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+		if key != '\t' {
+			return "", 0, false
+		}
+		newLine, newPos, candidates := complete(line, pos)
+		if len(candidates) > 1 {
+			_, _ = t.Write([]byte(strings.Join(candidates, "  ") + "\n"))
+		}
+		return newLine, newPos, true
+	}
+
+	readLine = func() (line string, err error) {
+		// the raw mode is for reading only, so that the outputs of the
+		// commands are not affected
+		var state *terminal.State
+		if state, err = terminal.MakeRaw(fd); err != nil {
+			return
+		}
+		defer func() { _ = terminal.Restore(fd, state) }()
+		if line, err = t.ReadLine(); err == terminal.ErrPasteIndicator {
+			err = nil
+		}
+		return
+	}
+	return readLine, true
+}
@@ -0,0 +1,15 @@
+// +build nacl
+
+// Copyright © 2020 Hedzr Yeh.
+
+package cmdr
+
+import (
+	"io"
+)
+
+// shellTerminal is not supported in nacl, the lines are read from in
+// directly.
+func (w *ExecWorker) shellTerminal(in io.Reader, prompt string, complete func(line string, pos int) (string, int, []string)) (readLine func() (string, error), ok bool) {
+	return nil, false
+}
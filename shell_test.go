@@ -0,0 +1,72 @@
+// Copyright © 2020 Hedzr Yeh.
+
+package cmdr_test
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/hedzr/cmdr"
+	"strings"
+	"testing"
+)
+
+func TestShell(t *testing.T) {
+	var w *cmdr.ExecWorker
+	var got []string
+	root := cmdr.Root("sh", "1.0.0")
+	cmd := root.NewSubCommand("server").
+		Action(func(cmd *cmdr.Command, args []string) (err error) {
+			opts, key := optionsOf(w, "server")
+			got = append(got, fmt.Sprintf("port=%v v=%v tag=%q t=%v %q", opts.GetIntEx(key("port")),
+				opts.GetBoolEx(key("verbose")), opts.GetString(key("tag")), opts.GetIntEx(key("trace")), args))
+			return
+		})
+	cmdr.NewInt(8080).Titles("port", "p").AttachTo(cmd)
+	cmdr.NewBool().Titles("verbose", "v").AttachTo(cmd)
+	cmdr.NewString("x").Titles("tag", "").AttachTo(cmd)
+	cmdr.NewCounter().Titles("trace", "t").AttachTo(cmd)
+	root.NewSubCommand("inc").
+		Action(func(cmd *cmdr.Command, args []string) (err error) {
+			n := w.GetOptions().GetIntEx(w.WrapWithRxxtPrefix("count")) + 1
+			w.GetOptions().Set("count", n)
+			got = append(got, fmt.Sprintf("count=%v", n))
+			return
+		})
+
+	script := `server --port 9000 -v -tt a
+server
+# a comment
+
+server --tag 'a b' "c d"
+inc
+inc
+server -t
+bogus
+shell
+exit
+server
+`
+	var errX *bytes.Buffer
+	w, _, errX = newTestWorker(root.RootCommand(),
+		cmdr.WithShellCommand(true),
+		cmdr.WithInternalInputStream(strings.NewReader(script)),
+	)
+	if err := w.Run([]string{"sh", "shell"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		`port=9000 v=true tag="x" t=2 ["a"]`,
+		`port=8080 v=false tag="x" t=0 []`,
+		`port=8080 v=false tag="a b" t=0 ["c d"]`,
+		`count=1`,
+		`count=2`,
+		`port=8080 v=false tag="x" t=1 []`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expect:\n%v\nbut got:\n%v", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+	if !strings.Contains(errX.String(), "already in the shell") {
+		t.Errorf("expect the nested shell refused, but got:\n%v", errX.String())
+	}
+}
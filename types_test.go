@@ -5,8 +5,10 @@
 package cmdr
 
 import (
+	"fmt"
 	"github.com/hedzr/cmdr/tool"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -94,3 +96,71 @@ func TestSoundeX(t *testing.T) {
 		t.Logf("soundex of '%v' = %v", str, tool.Soundex(str))
 	}
 }
+
+func TestParseSize(t *testing.T) {
+	for _, tc := range []struct {
+		src  string
+		want uint64
+		text string // the formatted text, or the expected error
+	}{
+		{"512", 512, "512B"},
+		{"0", 0, "0B"},
+		{"1k", 1000, "1kB"},
+		{"1 KiB", 1024, "1KiB"},
+		{"64MiB", 64 << 20, "64MiB"},
+		{"64mi", 64 << 20, "64MiB"},
+		{"1.5GB", 1500000000, "1500MB"},
+		{"1.5GiB", 3 << 29, "1536MiB"},
+		{"5tb", 5e12, "5TB"},
+		{"1234B", 1234, "1234B"},
+		{"2EiB", 2 << 60, "2EiB"},
+		{"", 0, `"" is not a size`},
+		{"MB", 0, `"MB" is not a size`},
+		{"12XB", 0, `"12XB" has an unknown unit "XB"`},
+		{"12 iB", 0, `unknown unit "iB"`},
+		{"1.2.3MB", 0, `"1.2.3" is not a valid number`},
+		{"20EB", 0, `"20EB" is too large`},
+		{"-1k", 0, `"-1k" is not a size`},
+	} {
+		n, err := parseSize(tc.src)
+		if err != nil {
+			if !strings.Contains(err.Error(), tc.text) {
+				t.Errorf("parseSize(%q): expect error %q but got %v", tc.src, tc.text, err)
+			}
+			continue
+		}
+		if n != tc.want || formatSize(n) != tc.text {
+			t.Errorf("parseSize(%q): expect %v (%v) but got %v (%v)", tc.src, tc.want, tc.text, n, formatSize(n))
+		}
+	}
+}
+
+func TestSplitResponseFile(t *testing.T) {
+	for _, tc := range []struct {
+		src  string
+		want string // the %q of the args, or the expected error
+	}{
+		{"", `[]`},
+		{"-a  -b\n\t--name x\r\n", `["-a" "-b" "--name" "x"]`},
+		{"# a comment\n-a # another\n-b", `["-a" "-b"]`},
+		{"a#b", `["a#b"]`},
+		{`'a b' "c d" e\ f`, `["a b" "c d" "e f"]`},
+		{`'it''s' "say \"hi\" \n" 'no \escape'`, `["its" "say \"hi\" \\n" "no \\escape"]`},
+		{"-a \\\n-b \"x\\\ny\"", `["-a" "-b" "xy"]`},
+		{`--name=''`, `["--name="]`},
+		{`''`, `[""]`},
+		{`'abc`, `unterminated single quote`},
+		{`"abc`, `unterminated double quote`},
+	} {
+		args, _, err := splitResponseFile(tc.src)
+		if err != nil {
+			if !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("splitResponseFile(%q): expect error %q but got %v", tc.src, tc.want, err)
+			}
+			continue
+		}
+		if got := fmt.Sprintf("%q", args); got != tc.want {
+			t.Errorf("splitResponseFile(%q): expect %v but got %v", tc.src, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright © 2020 Hedzr Yeh.
+
+package cmdr
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The builtin Value types for time.Time, net.IP, *net.IPNet, *url.URL
+// and size flags. They are created by NewTime, NewIP, NewIPNet, NewURL
+// and NewSize, or from a DefaultValue in these types, see also toValue.
+
+type (
+	timeValue struct {
+		t      time.Time
+		layout string
+	}
+
+	ipValue struct {
+		ip net.IP
+	}
+
+	ipNetValue struct {
+		n *net.IPNet
+	}
+
+	urlValue struct {
+		u *url.URL
+	}
+
+	sizeValue struct {
+		n uint64
+	}
+
+	// nativeValue is a Value which holds a value of the go builtin
+	// type, it's passed to the OnSet handlers instead of the Value.
+	nativeValue interface {
+		native() interface{}
+	}
+)
+
+func (v *timeValue) String() string {
+	if v.t.IsZero() {
+		return ""
+	}
+	return v.t.Format(v.layout)
+}
+
+func (v *timeValue) Type() string { return "time" }
+
+func (v *timeValue) Set(s string) (err error) {
+	var t time.Time
+	if t, err = time.Parse(v.layout, s); err == nil {
+		v.t = t
+	}
+	return
+}
+
+func (v *timeValue) native() interface{} { return v.t }
+
+func (v *ipValue) String() string {
+	if v.ip == nil {
+		return ""
+	}
+	return v.ip.String()
+}
+
+func (v *ipValue) Type() string { return "ip" }
+
+func (v *ipValue) Set(s string) (err error) {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return fmt.Errorf("%q is not a valid IPv4 or IPv6 address", s)
+	}
+	v.ip = ip
+	return
+}
+
+func (v *ipValue) native() interface{} { return v.ip }
+
+func (v *ipNetValue) String() string {
+	if v.n == nil {
+		return ""
+	}
+	return v.n.String()
+}
+
+func (v *ipNetValue) Type() string { return "cidr" }
+
+func (v *ipNetValue) Set(s string) (err error) {
+	var n *net.IPNet
+	if _, n, err = net.ParseCIDR(s); err != nil {
+		return fmt.Errorf("%q is not a valid CIDR notation, such as 192.0.2.0/24", s)
+	}
+	v.n = n
+	return
+}
+
+func (v *ipNetValue) native() interface{} { return v.n }
+
+func (v *urlValue) String() string {
+	if v.u == nil {
+		return ""
+	}
+	return v.u.String()
+}
+
+func (v *urlValue) Type() string { return "url" }
+
+func (v *urlValue) Set(s string) (err error) {
+	var u *url.URL
+	if u, err = url.Parse(s); err != nil {
+		return
+	}
+	if u.Scheme == "" {
+		return fmt.Errorf("%q is not an absolute URL, the scheme is missing", s)
+	}
+	v.u = u
+	return
+}
+
+func (v *urlValue) native() interface{} { return v.u }
+
+func (v *sizeValue) String() string { return formatSize(v.n) }
+
+func (v *sizeValue) Type() string { return "size" }
+
+func (v *sizeValue) Set(s string) (err error) {
+	var n uint64
+	if n, err = parseSize(s); err == nil {
+		v.n = n
+	}
+	return
+}
+
+func (v *sizeValue) native() interface{} { return v.n }
+
+// parseSize parses a human readable size, such as "64MiB", "1.5GB" or
+// "512". The SI suffixes k, M, G, T, P, E are based 1000, and the IEC
+// suffixes Ki, Mi, Gi, Ti, Pi, Ei are based 1024, the trailing 'B' is
+// optional. All of them are case-insensitive.
+func parseSize(s string) (n uint64, err error) {
+	text := strings.TrimSpace(s)
+	i := strings.IndexFunc(text, func(r rune) bool { return !(r >= '0' && r <= '9' || r == '.') })
+	if i < 0 {
+		i = len(text)
+	}
+	num, unit := text[:i], strings.TrimSpace(text[i:])
+	if num == "" {
+		return 0, fmt.Errorf("%q is not a size, such as 512, 64MiB or 1.5GB", s)
+	}
+	factor, ok := sizeFactor(unit)
+	if !ok {
+		return 0, fmt.Errorf("%q has an unknown unit %q, expecting B, kB, MB, GB, TB, PB, EB or KiB, MiB, GiB, TiB, PiB, EiB", s, unit)
+	}
+
+	if strings.Contains(num, ".") {
+		var f float64
+		if f, err = strconv.ParseFloat(num, 64); err != nil {
+			return 0, fmt.Errorf("%q is not a valid number", num)
+		}
+		if f *= float64(factor); f >= math.MaxUint64 {
+			return 0, fmt.Errorf("%q is too large", s)
+		}
+		return uint64(f), nil
+	}
+	if n, err = strconv.ParseUint(num, 10, 64); err != nil || n > math.MaxUint64/factor {
+		return 0, fmt.Errorf("%q is too large", s)
+	}
+	return n * factor, nil
+}
+
+// sizeFactor returns the bytes of a size unit, such as "MiB", "kB".
+func sizeFactor(unit string) (factor uint64, ok bool) {
+	u := strings.TrimSuffix(strings.ToLower(unit), "b")
+	base := uint64(1000)
+	if strings.HasSuffix(u, "i") {
+		base, u = 1024, u[:len(u)-1]
+		if u == "" {
+			return
+		}
+	}
+	if u == "" {
+		return 1, true
+	}
+	ix := strings.Index(sizeUnits, u)
+	if len(u) != 1 || ix < 0 {
+		return
+	}
+	factor, ok = 1, true
+	for i := 0; i <= ix; i++ {
+		factor *= base
+	}
+	return
+}
+
+const sizeUnits = "kmgtpe"
+
+// formatSize returns n in the unit which divides it exactly with the
+// smallest quotient, the IEC unit is preferred if both fit, such as
+// "64MiB", "5TB", "1234B".
+func formatSize(n uint64) string {
+	text, q := fmt.Sprintf("%dB", n), n
+	for _, iec := range []bool{true, false} {
+		for i := len(sizeUnits); i > 0; i-- {
+			u := sizeUnits[i-1 : i]
+			if iec {
+				u += "i"
+			}
+			if f, _ := sizeFactor(u); n >= f && n%f == 0 {
+				if n/f < q {
+					text, q = fmt.Sprintf("%d%sB", n/f, strings.Replace(strings.ToUpper(u), "I", "i", 1)), n/f
+				}
+				break
+			}
+		}
+	}
+	return strings.Replace(text, "KB", "kB", 1)
+}
+
+// toValue returns the Value for the DefaultValue v of a flag: v itself
+// if it's a Value, or the builtin one if it's a time.Time, net.IP,
+// *net.IPNet or *url.URL, or a size if prev is a size. The layout of a
+// time is taken from prev. It returns nil for the others.
+func toValue(v interface{}, prev Value) Value {
+	switch x := v.(type) {
+	case Value:
+		return x
+	case time.Time:
+		layout := time.RFC3339
+		if tv, ok := prev.(*timeValue); ok {
+			layout = tv.layout
+		}
+		return &timeValue{t: x, layout: layout}
+	case net.IP:
+		return &ipValue{ip: x}
+	case *net.IPNet:
+		return &ipNetValue{n: x}
+	case *url.URL:
+		return &urlValue{u: x}
+	}
+	if _, ok := prev.(*sizeValue); ok {
+		if n, err := strconv.ParseUint(fmt.Sprint(v), 10, 64); err == nil {
+			return &sizeValue{n: n}
+		}
+	}
+	return nil
+}
+
+// valueText returns the text of val, which is loaded from the config
+// files, for v.Set. A timestamp decoded by the config file loader is
+// formatted with the layout of v.
+func valueText(v interface{}, val interface{}) string {
+	if t, ok := val.(time.Time); ok {
+		if tv, ok := v.(*timeValue); ok {
+			return t.Format(tv.layout)
+		}
+	}
+	return fmt.Sprint(val)
+}
+
+// nativeOf returns the go builtin value held by v, or v itself.
+func nativeOf(v interface{}) interface{} {
+	if x, ok := v.(nativeValue); ok {
+		return x.native()
+	}
+	return v
+}
@@ -0,0 +1,225 @@
+// Copyright © 2020 Hedzr Yeh.
+
+package cmdr_test
+
+import (
+	"fmt"
+	"github.com/hedzr/cmdr"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+type ipValue struct{ ip net.IP }
+
+func (v *ipValue) String() string { return v.ip.String() }
+func (v *ipValue) Type() string   { return "ip" }
+func (v *ipValue) Set(s string) error {
+	if v.ip = net.ParseIP(s); v.ip == nil {
+		return fmt.Errorf("%q is not an IP address", s)
+	}
+	return nil
+}
+
+type listValue struct{ items []string }
+
+func (v *listValue) String() string { return strings.Join(v.items, ",") }
+func (v *listValue) Type() string   { return "list" }
+func (v *listValue) Set(s string) error {
+	v.items = append(v.items, s)
+	return nil
+}
+
+// labelsValue is an uncomparable Value.
+type labelsValue struct{ m map[string]string }
+
+func (v labelsValue) Type() string { return "labels" }
+func (v labelsValue) String() string {
+	var a []string
+	for k, x := range v.m {
+		a = append(a, k+"="+x)
+	}
+	sort.Strings(a)
+	return strings.Join(a, ",")
+}
+func (v labelsValue) Set(s string) error {
+	for _, item := range strings.Split(s, ",") {
+		if kv := strings.SplitN(item, "=", 2); len(kv) == 2 {
+			v.m[kv[0]] = kv[1]
+		} else if item != "" {
+			return fmt.Errorf("%q is not a key=value pair", item)
+		}
+	}
+	return nil
+}
+
+func TestValueFlags(t *testing.T) {
+	tags := &listValue{}
+	c := &cmdrTester{T: t}
+	c.newRoot = func() *cmdr.RootCommand {
+		root := cmdr.Root("valued", "1.0.0")
+		cmd := root.NewSubCommand("serve").Action(c.action)
+		cmdr.NewValue(&ipValue{ip: net.IPv4(127, 0, 0, 1)}).
+			Titles("addr", "a").
+			EnvKeys("VALUED_ADDR").
+			AttachTo(cmd)
+		cmdr.NewValue(tags).
+			Titles("tag", "t").
+			AttachTo(cmd)
+		cmdr.NewValue(labelsValue{m: make(map[string]string)}).
+			Titles("label", "l").
+			AttachTo(cmd)
+		return root.RootCommand()
+	}
+	c.result = func(w *cmdr.ExecWorker) string {
+		opts, key := optionsOf(w, "serve")
+		addr, _ := opts.Get(key("addr")).(*ipValue) // <nil> unless it's an *ipValue
+		return fmt.Sprintf("addr=%v tag=%v label=%v", addr, opts.Get(key("tag")), opts.Get(key("label")))
+	}
+
+	c.expect(conformance{
+		{"serve", "addr=127.0.0.1 tag= label="},
+		{"serve --addr 10.0.0.1 -t a --tag b", "addr=10.0.0.1 tag=a,b label="},
+		{"serve --tag c", "addr=127.0.0.1 tag=c label="},
+		// an uncomparable Value given more than once
+		{"serve -l a=1 --label b=2", "addr=127.0.0.1 tag= label=a=1,b=2"},
+		{"serve --addr x", `invalid ip value 'x' for option '--addr' (from command-line): "x" is not an IP address`},
+	})
+	if len(tags.items) != 0 {
+		t.Errorf("the default value should not be touched, but got %v", tags.items)
+	}
+
+	c.expectEnv("VALUED_ADDR", "10.1.1.1", conformance{
+		{"serve", "addr=10.1.1.1 tag= label="},
+	})
+	c.expectEnv("VALUED_ADDR", "localhost", conformance{
+		{"serve", "invalid ip value 'localhost' for option '--addr' (from env var VALUED_ADDR)"},
+	})
+	c.expectConfig("app:\n  serve:\n    addr: 192.168.0.1\n", conformance{
+		{"serve", "addr=192.168.0.1 tag= label="},
+	})
+	c.expectOutput("serve --help", "--addr=IP", "(default IP=127.0.0.1)", "--tag=LIST")
+}
+
+func TestNativeValueFlags(t *testing.T) {
+	var onSet []interface{}
+	c := &cmdrTester{T: t}
+	c.newRoot = func() *cmdr.RootCommand {
+		root := cmdr.Root("native", "1.0.0")
+		cmd := root.NewSubCommand("dial").Action(c.action)
+		cmdr.NewTime("2006-01-02").Titles("since", "").AttachTo(cmd)
+		cmdr.NewIP(net.IPv4(127, 0, 0, 1)).Titles("addr", "").EnvKeys("NATIVE_ADDR").AttachTo(cmd)
+		cmdr.NewIPNet().Titles("allow", "").AttachTo(cmd)
+		cmdr.NewURL().Titles("proxy", "").
+			OnSet(func(keyPath string, value interface{}) { onSet = append(onSet, value) }).
+			AttachTo(cmd)
+		cmd.ToCommand().Flags = append(cmd.ToCommand().Flags, &cmdr.Flag{
+			BaseOpt:      cmdr.BaseOpt{Full: "until"},
+			DefaultValue: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		})
+		return root.RootCommand()
+	}
+	c.result = func(w *cmdr.ExecWorker) string {
+		opts, key := optionsOf(w, "dial")
+		return fmt.Sprintf("%v %v %v %v %v",
+			opts.GetTime(key("since")).Format("2006-01-02"), opts.GetTime(key("until")).Unix(),
+			opts.GetIP(key("addr")), opts.GetIPNet(key("allow")), opts.GetURL(key("proxy")),
+		)
+	}
+
+	c.expect(conformance{
+		{"dial", "0001-01-01 1577934245 127.0.0.1 <nil> <nil>"},
+		{"dial --since 2020-05-06 --until 2021-01-01T00:00:00Z --addr ::1 --allow 10.1.2.3/8 --proxy http://p:3128",
+			"2020-05-06 1609459200 ::1 10.0.0.0/8 http://p:3128"},
+		{"dial --since 2020/05/06", `invalid time value '2020/05/06' for option '--since' (from command-line): parsing time "2020/05/06" as "2006-01-02": cannot parse "/05/06" as "-"`},
+		{"dial --addr 10.0.0.256", `invalid ip value '10.0.0.256' for option '--addr' (from command-line): "10.0.0.256" is not a valid IPv4 or IPv6 address`},
+		{"dial --allow 10.0.0.0", `invalid cidr value '10.0.0.0' for option '--allow' (from command-line): "10.0.0.0" is not a valid CIDR notation`},
+		{"dial --proxy ://p", `invalid url value '://p' for option '--proxy' (from command-line): parse`},
+		{"dial --proxy localhost", `"localhost" is not an absolute URL, the scheme is missing`},
+	})
+	if len(onSet) == 0 {
+		t.Error("OnSet: expect being called")
+	} else if u, ok := onSet[0].(*url.URL); !ok || u.Host != "p:3128" {
+		t.Errorf("OnSet: expect a *url.URL but got %#v", onSet[0])
+	}
+
+	c.expectEnv("NATIVE_ADDR", "192.168.1.1", conformance{
+		{"dial", "0001-01-01 1577934245 192.168.1.1 <nil> <nil>"},
+	})
+	c.expectEnv("NATIVE_ADDR", "host", conformance{
+		{"dial", "(from env var NATIVE_ADDR)"},
+	})
+	c.expectConfig("app:\n  dial:\n    since: 2019-12-31\n    allow: 192.168.0.0/16\n", conformance{
+		{"dial", "2019-12-31 1577934245 127.0.0.1 192.168.0.0/16 <nil>"},
+	})
+	c.expectConfig("app:\n  dial:\n    since: 12/31/2019\n", conformance{
+		{"dial", "invalid time value '12/31/2019' for option '--since' (from config)"},
+	})
+	c.expectOutput("dial --help", "--since=TIME", "--addr=IP", "(default IP=127.0.0.1)", "--allow=CIDR", "--proxy=URL", "(default TIME=2020-01-02T03:04:05Z)")
+
+	// the DefaultValues are kept in their types, and a string is parsed
+	// with the layout of the flag
+	root := c.newRoot()
+	w, _, _, err := runWorker(root, []string{"native", "dial"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, flg := range root.SubCommands[0].Flags {
+		var ok bool
+		switch flg.Full {
+		case "since", "until":
+			_, ok = flg.DefaultValue.(time.Time)
+		case "addr":
+			_, ok = flg.DefaultValue.(net.IP)
+		case "allow":
+			_, ok = flg.DefaultValue.(*net.IPNet)
+		case "proxy":
+			_, ok = flg.DefaultValue.(*url.URL)
+		default:
+			ok = true
+		}
+		if !ok {
+			t.Errorf("expect the DefaultValue of --%v kept, but got %T", flg.Full, flg.DefaultValue)
+		}
+	}
+	w.GetOptions().Set("dial.since", "2020-05-06")
+	if got := w.GetOptions().GetTime(w.WrapWithRxxtPrefix("dial.since")); got.Format("2006-01-02") != "2020-05-06" {
+		t.Errorf("GetTime: expect 2020-05-06 parsed with the layout of --since, but got %v", got)
+	}
+}
+
+func TestSizeFlags(t *testing.T) {
+	c := &cmdrTester{T: t}
+	c.newRoot = func() *cmdr.RootCommand {
+		root := cmdr.Root("sized", "1.0.0")
+		cmd := root.NewSubCommand("serve").Action(c.action)
+		cmdr.NewSize(64<<20).Titles("buffer", "b").EnvKeys("SIZED_BUFFER").AttachTo(cmd)
+		cmd.NewFlag(cmdr.OptFlagTypeHumanReadableSize).Titles("limit", "")
+		return root.RootCommand()
+	}
+	c.result = func(w *cmdr.ExecWorker) string {
+		opts, key := optionsOf(w, "serve")
+		return fmt.Sprintf("%v %v", opts.GetKibibytesEx(key("buffer")), opts.GetKilobytesEx(key("limit")))
+	}
+
+	c.expect(conformance{
+		{"serve", "67108864 0"},
+		{"serve --buffer 1.5GB --limit 2KiB", "1500000000 2048"},
+		{"serve -b 4k --limit=10", "4000 10"},
+		{"serve --buffer 12XB", `invalid size value '12XB' for option '--buffer' (from command-line): "12XB" has an unknown unit "XB"`},
+		{"serve --limit lots", `invalid size value 'lots' for option '--limit' (from command-line): "lots" is not a size`},
+	})
+	c.expectEnv("SIZED_BUFFER", "2KiB", conformance{
+		{"serve", "2048 0"},
+	})
+	c.expectEnv("SIZED_BUFFER", "2 KB/s", conformance{
+		{"serve", "(from env var SIZED_BUFFER)"},
+	})
+	c.expectConfig("app:\n  serve:\n    buffer: 4096\n    limit: 1MB\n", conformance{
+		{"serve", "4096 1000000"},
+	})
+	c.expectOutput("serve --help", "--buffer=SIZE", "(default SIZE=64MiB)", "--limit=SIZE")
+}